@@ -0,0 +1,73 @@
+package norm
+
+// aggregateColName the column name of the aggregated value
+const aggregateColName = "norm_aggregate"
+
+// Count pipes the result of the statement into 'YIELD count(*)' and assigns the number of records to count
+//
+//	var count int64
+//	err := db.Go().From("player100").Over("follow").Yield("dst(edge) AS id").Count(&count)
+func (db *DB) Count(count *int64) error {
+	return db.aggregate("count(*)", count)
+}
+
+// Exists reports whether the statement returns any record, only the first record is counted
+//
+//	exists, err := db.Lookup("player").Where("player.name == ?", "Tim Duncan").Yield("id(vertex)").Exists()
+func (db *DB) Exists() (bool, error) {
+	tx := db.getInstance()
+	page, err := tx.Statement.Page(0, 1)
+	if err != nil {
+		return false, err
+	}
+	tx = tx.newSession()
+	tx.Statement = page
+	var count int64
+	if err = tx.Count(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Sum pipes the result of the statement into 'YIELD sum(expr)' and assigns the sum to dest, the columns of the
+// result are referenced by $-, eg: $-.age
+//
+//	var total int64
+//	err := db.Lookup("player").Yield("properties(vertex).age AS age").Sum("$-.age", &total)
+func (db *DB) Sum(expr string, dest any) error {
+	return db.aggregate("sum("+expr+")", dest)
+}
+
+// Avg pipes the result of the statement into 'YIELD avg(expr)' and assigns the average to dest
+// see more information on Sum
+func (db *DB) Avg(expr string, dest any) error {
+	return db.aggregate("avg("+expr+")", dest)
+}
+
+// Min pipes the result of the statement into 'YIELD min(expr)' and assigns the minimum to dest
+// see more information on Sum
+func (db *DB) Min(expr string, dest any) error {
+	return db.aggregate("min("+expr+")", dest)
+}
+
+// Max pipes the result of the statement into 'YIELD max(expr)' and assigns the maximum to dest
+// see more information on Sum
+func (db *DB) Max(expr string, dest any) error {
+	return db.aggregate("max("+expr+")", dest)
+}
+
+// aggregate pipes the result of the statement into the aggregate function and assigns the value to dest
+func (db *DB) aggregate(fn string, dest any) error {
+	tx := db.getInstance()
+	stmt, err := tx.Statement.Aggregate(fn + " AS " + aggregateColName)
+	if err != nil {
+		return err
+	}
+	tx = tx.newSession()
+	tx.Statement = stmt
+	rawRes, err := tx.execute()
+	if err != nil {
+		return err
+	}
+	return pluck(rawRes, aggregateColName, dest, true)
+}
@@ -0,0 +1,66 @@
+package norm
+
+import (
+	"context"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"testing"
+)
+
+func TestAggregate(t *testing.T) {
+	var (
+		nGQL  string
+		value *nebulaType.Value
+	)
+	db := &DB{conf: &Config{logger: logger.Default.LogMode(logger.SilentLevel)}, clone: 1}
+	assert.NoError(t, db.Use(stubPlugin(func(ctx context.Context, query *Query) (*nebula.ResultSet, error) {
+		nGQL = query.NGQL
+		return nebula.GenResultSet(&graph.ExecutionResponse{
+			Data: &nebulaType.DataSet{
+				ColumnNames: [][]byte{[]byte(aggregateColName)},
+				Rows:        []*nebulaType.Row{{Values: []*nebulaType.Value{value}}},
+			},
+		})
+	})))
+
+	count := int64(3)
+	value = &nebulaType.Value{IVal: &count}
+	var n int64
+	assert.NoError(t, db.Go().From("player100").Over("follow").Yield("dst(edge) AS id").Count(&n))
+	assert.Equal(t, int64(3), n)
+	assert.Equal(t, `GO FROM "player100" OVER follow YIELD dst(edge) AS id | YIELD count(*) AS norm_aggregate;`, nGQL)
+
+	exists, err := db.Lookup("player").Yield("id(vertex)").Exists()
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, `LOOKUP ON player YIELD id(vertex) | LIMIT 1 | YIELD count(*) AS norm_aggregate;`, nGQL)
+
+	count = 0
+	exists, err = db.Lookup("player").Yield("id(vertex)").Exists()
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	sum := int64(78)
+	value = &nebulaType.Value{IVal: &sum}
+	var total int
+	assert.NoError(t, db.Lookup("player").Yield("properties(vertex).age AS age").Sum("$-.age", &total))
+	assert.Equal(t, 78, total)
+	assert.Equal(t, `LOOKUP ON player YIELD properties(vertex).age AS age | YIELD sum($-.age) AS norm_aggregate;`, nGQL)
+
+	avg := 39.0
+	value = &nebulaType.Value{FVal: &avg}
+	var avgAge float64
+	assert.NoError(t, db.Lookup("player").Yield("properties(vertex).age AS age").Avg("$-.age", &avgAge))
+	assert.Equal(t, 39.0, avgAge)
+	assert.Equal(t, `LOOKUP ON player YIELD properties(vertex).age AS age | YIELD avg($-.age) AS norm_aggregate;`, nGQL)
+
+	value = &nebulaType.Value{SVal: []byte("Tim Duncan")}
+	var name string
+	assert.NoError(t, db.Lookup("player").Yield("properties(vertex).name AS name").Min("$-.name", &name))
+	assert.Equal(t, "Tim Duncan", name)
+	assert.NoError(t, db.Lookup("player").Yield("properties(vertex).name AS name").Max("$-.name", &name))
+	assert.Equal(t, `LOOKUP ON player YIELD properties(vertex).name AS name | YIELD max($-.name) AS norm_aggregate;`, nGQL)
+}
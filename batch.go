@@ -0,0 +1,217 @@
+package norm
+
+import (
+	"fmt"
+	"github.com/haysons/norm/resolver"
+	"github.com/haysons/norm/statement"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BatchError is returned by the methods executing statements in batches, eg: CreateInBatches, it reports the
+// batches that failed, the other batches were executed successfully.
+type BatchError struct {
+	// Total number of batches
+	Total int
+
+	// Failures the failed batches, sorted by index
+	Failures []BatchFailure
+}
+
+// BatchFailure is a failed batch
+type BatchFailure struct {
+	// Index of the batch, starting at 0. the values of the batch are values[Index*batchSize:(Index+1)*batchSize]
+	Index int
+
+	// Err the error returned by the batch
+	Err error
+}
+
+func (e *BatchError) Error() string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("norm: %d of %d batches failed", len(e.Failures), e.Total))
+	for i, failure := range e.Failures {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(fmt.Sprintf("batch %d: %v", failure.Index, failure.Err))
+	}
+	return b.String()
+}
+
+// Unwrap returns the errors of the failed batches
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, failure := range e.Failures {
+		errs = append(errs, failure.Err)
+	}
+	return errs
+}
+
+// CreateInBatches inserts the vertexes or edges of values in batches of batchSize, each batch is a single INSERT
+// statement, and at most concurrency batches are executed concurrently. values must be a slice or an array of
+// vertexes or edges, refer to InsertVertex and InsertEdge for the requirements of them.
+// If some batches fail, a *BatchError is returned reporting the failed batches and the errors, the other batches
+// are still inserted.
+//
+//	err := db.CreateInBatches(players, 500, 4)
+func (db *DB) CreateInBatches(values any, batchSize, concurrency int, ifNotExists ...bool) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("norm: %w, batch size should be greater than 0", ErrInvalidValue)
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	value := reflect.Indirect(reflect.ValueOf(values))
+	switch value.Kind() {
+	case reflect.Slice:
+	case reflect.Array:
+		if !value.CanAddr() {
+			// slicing an array requires it to be addressable
+			arr := reflect.New(value.Type()).Elem()
+			arr.Set(value)
+			value = arr
+		}
+	default:
+		return fmt.Errorf("norm: %w, values should be a slice or an array of vertexes or edges", ErrInvalidValue)
+	}
+	if value.Len() == 0 {
+		return nil
+	}
+	isEdge := isEdgeValue(value.Index(0))
+
+	total := (value.Len() + batchSize - 1) / batchSize
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		failures []BatchFailure
+	)
+	sem := make(chan struct{}, concurrency)
+	for i := 0; i < total; i++ {
+		end := (i + 1) * batchSize
+		if end > value.Len() {
+			end = value.Len()
+		}
+		batch := value.Slice(i*batchSize, end).Interface()
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(index int, batch any) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			tx := db.newSession()
+			if isEdge {
+				tx = tx.InsertEdge(batch, ifNotExists...)
+			} else {
+				tx = tx.InsertVertex(batch, ifNotExists...)
+			}
+			if err := tx.Exec(); err != nil {
+				mu.Lock()
+				failures = append(failures, BatchFailure{Index: index, Err: err})
+				mu.Unlock()
+			}
+		}(i, batch)
+	}
+	wg.Wait()
+	if len(failures) == 0 {
+		return nil
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Index < failures[j].Index
+	})
+	return &BatchError{Total: total, Failures: failures}
+}
+
+// FindInBatches pages through the result of the statement, batchSize records at a time. each page is queried by
+// piping the statement into 'LIMIT offset, batchSize', assigned to dest, and then passed to fc along with the batch
+// number starting at 0. tx passed to fc is a new session to execute other statements, eg: writing the records of
+// the page. the iteration stops when a page is not full or fc returns an error, which is returned.
+// It is intended for GO and LOOKUP statements, add ORDER BY to the statement so that the pages are stable.
+//
+//	players := make([]*Player, 0)
+//	err := db.Lookup("player").Yield("vertex AS v").OrderBy("$-.v").FindInBatches(&players, 1000, func(tx *norm.DB, batch int) error {
+//		// process players of the batch
+//		return nil
+//	})
+func (db *DB) FindInBatches(dest any, batchSize int, fc func(tx *DB, batch int) error) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("norm: %w, batch size should be greater than 0", ErrInvalidValue)
+	}
+	tx := db.getInstance()
+	for batch := 0; ; batch++ {
+		page, err := tx.Statement.Page(batch*batchSize, batchSize)
+		if err != nil {
+			return err
+		}
+		pageTx := tx.newSession()
+		pageTx.Statement = page
+		rawRes, err := pageTx.execute()
+		if err != nil {
+			return err
+		}
+		if rawRes.GetRowSize() == 0 {
+			return nil
+		}
+		resetSlice(dest)
+		if err = Scan(rawRes, dest); err != nil {
+			return err
+		}
+		if err = callAfterFind(pageTx.Context(), dest); err != nil {
+			return err
+		}
+		// fc gets a new session, so that the statements it builds are not mixed up with the statement of the page
+		session := tx.newSession()
+		session.clone = 1
+		if err = fc(session, batch); err != nil {
+			return err
+		}
+		if rawRes.GetRowSize() < batchSize {
+			return nil
+		}
+	}
+}
+
+// resetSlice truncates the slice that dest points to, so that the records of the previous page are dropped
+func resetSlice(dest any) {
+	destValue := reflect.ValueOf(dest)
+	if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+		return
+	}
+	destValue = destValue.Elem()
+	if destValue.Kind() == reflect.Slice && !destValue.IsNil() {
+		destValue.SetLen(0)
+	}
+}
+
+// newSession returns a DB with a new statement, which keeps the settings of the current DB, such as the context,
+// the space and the per-call overrides. it is used to execute statements concurrently.
+func (db *DB) newSession() *DB {
+	tx := *db
+	tx.Statement = statement.New()
+	if db.conf.ParameterizedQuery {
+		tx.Statement.Parameterize()
+	}
+	tx.clone = 0
+	tx.hookModels = nil
+	return &tx
+}
+
+// isEdgeValue reports whether the value is an edge, otherwise it is treated as a vertex
+func isEdgeValue(value reflect.Value) bool {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return false
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := reflect.New(value.Type()).Interface().(resolver.EdgeTypeNamer)
+	return ok
+}
@@ -0,0 +1,165 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type batchFollow struct {
+	SrcID  string `norm:"edge_src_id"`
+	DstID  string `norm:"edge_dst_id"`
+	Degree int    `norm:"prop:degree"`
+}
+
+func (f batchFollow) EdgeTypeName() string {
+	return "follow"
+}
+
+func TestCreateInBatches(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		nGQLs []string
+	)
+	db := &DB{conf: &Config{logger: logger.Default.LogMode(logger.SilentLevel)}, clone: 1}
+	err := db.Use(stubPlugin(func(ctx context.Context, query *Query) (*nebula.ResultSet, error) {
+		mu.Lock()
+		nGQLs = append(nGQLs, query.NGQL)
+		mu.Unlock()
+		if strings.Contains(query.NGQL, `"player_fail"`) {
+			return nil, errors.New("insert failed")
+		}
+		return nebula.GenResultSet(&graph.ExecutionResponse{})
+	}))
+	assert.NoError(t, err)
+
+	players := []*hookPlayer{{Name: "p1"}, {Name: "p2"}, {Name: "p3"}, {Name: "p4"}, {Name: "p5"}}
+	assert.NoError(t, db.CreateInBatches(players, 2, 2))
+	sort.Strings(nGQLs)
+	assert.Equal(t, []string{
+		`INSERT VERTEX player(name) VALUES "player_p1":("p1"), "player_p2":("p2");`,
+		`INSERT VERTEX player(name) VALUES "player_p3":("p3"), "player_p4":("p4");`,
+		`INSERT VERTEX player(name) VALUES "player_p5":("p5");`,
+	}, nGQLs)
+
+	nGQLs = nil
+	follows := [3]batchFollow{{SrcID: "a", DstID: "b", Degree: 1}, {SrcID: "b", DstID: "c", Degree: 2}, {SrcID: "c", DstID: "d", Degree: 3}}
+	assert.NoError(t, db.CreateInBatches(follows, 3, 1, true))
+	assert.Equal(t, []string{
+		`INSERT EDGE IF NOT EXISTS follow(degree) VALUES "a"->"b":(1), "b"->"c":(2), "c"->"d":(3);`,
+	}, nGQLs)
+
+	players = []*hookPlayer{{Name: "p1"}, {Name: "fail"}, {Name: "p3"}, {Name: "p4"}, {VID: "player_fail", Name: "p5"}}
+	err = db.CreateInBatches(players, 1, 3)
+	var batchErr *BatchError
+	if assert.True(t, errors.As(err, &batchErr)) {
+		assert.Equal(t, 5, batchErr.Total)
+		assert.Len(t, batchErr.Failures, 2)
+		assert.Equal(t, 1, batchErr.Failures[0].Index)
+		assert.Equal(t, 4, batchErr.Failures[1].Index)
+	}
+	assert.EqualError(t, err, "norm: 2 of 5 batches failed: batch 1: insert failed; batch 4: insert failed")
+
+	assert.NoError(t, db.CreateInBatches([]*hookPlayer{}, 10, 1))
+	assert.ErrorIs(t, db.CreateInBatches(players, 0, 1), ErrInvalidValue)
+	assert.ErrorIs(t, db.CreateInBatches(players[0], 1, 1), ErrInvalidValue)
+}
+
+func TestFindInBatches(t *testing.T) {
+	names := []string{"p1", "p2", "p3", "p4", "p5"}
+	var nGQLs []string
+	db := &DB{conf: &Config{logger: logger.Default.LogMode(logger.SilentLevel)}, clone: 1}
+	assert.NoError(t, db.Use(stubPlugin(func(ctx context.Context, query *Query) (*nebula.ResultSet, error) {
+		nGQLs = append(nGQLs, query.NGQL)
+		var offset, limit int
+		if _, err := fmt.Sscanf(query.NGQL[strings.LastIndex(query.NGQL, "LIMIT"):], "LIMIT %d, %d;", &offset, &limit); err != nil {
+			offset = 0
+			_, _ = fmt.Sscanf(query.NGQL[strings.LastIndex(query.NGQL, "LIMIT"):], "LIMIT %d;", &limit)
+		}
+		end := offset + limit
+		if end > len(names) {
+			end = len(names)
+		}
+		if offset > end {
+			offset = end
+		}
+		return newPlayersResult(t, names[offset:end]...), nil
+	})))
+
+	players := make([]*rowsPlayer, 0)
+	batches := make([][]string, 0)
+	err := db.Lookup("player").Yield("id(vertex) AS vid, properties(vertex).name AS name").
+		FindInBatches(&players, 2, func(tx *DB, batch int) error {
+			assert.Equal(t, len(batches), batch)
+			pageNames := make([]string, 0, len(players))
+			for _, player := range players {
+				assert.True(t, player.found)
+				pageNames = append(pageNames, player.Name)
+			}
+			batches = append(batches, pageNames)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"p1", "p2"}, {"p3", "p4"}, {"p5"}}, batches)
+	assert.Equal(t, []string{
+		`LOOKUP ON player YIELD id(vertex) AS vid, properties(vertex).name AS name | LIMIT 2;`,
+		`LOOKUP ON player YIELD id(vertex) AS vid, properties(vertex).name AS name | LIMIT 2, 2;`,
+		`LOOKUP ON player YIELD id(vertex) AS vid, properties(vertex).name AS name | LIMIT 4, 2;`,
+	}, nGQLs)
+
+	// the iteration stops when the page is empty
+	nGQLs, batches = nil, batches[:0]
+	err = db.Lookup("player").Yield("id(vertex) AS vid").FindInBatches(&players, 5, func(tx *DB, batch int) error {
+		batches = append(batches, nil)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, nGQLs, 2)
+	assert.Len(t, batches, 1)
+
+	// the iteration stops when the callback returns an error
+	stopErr := errors.New("stop")
+	err = db.Lookup("player").Yield("id(vertex) AS vid").FindInBatches(&players, 1, func(tx *DB, batch int) error {
+		return stopErr
+	})
+	assert.Equal(t, stopErr, err)
+	assert.ErrorIs(t, db.Lookup("player").FindInBatches(&players, 0, nil), ErrInvalidValue)
+}
+
+func TestFindInBatchesWrite(t *testing.T) {
+	stmts := make([]string, 0)
+	executor := funcExecutor(func(stmt string) (*nebula.ResultSet, error) {
+		stmts = append(stmts, stmt)
+		if strings.HasPrefix(stmt, "LOOKUP") {
+			return newResult(t, []string{"vid", "name"}, []any{"player_p1", "p1"}), nil
+		}
+		return newResult(t, nil), nil
+	})
+	db, err := OpenWithExecutor(&Config{}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+
+	// the statements built on the session passed to the callback are executed
+	players := make([]*rowsPlayer, 0)
+	err = db.Lookup("player").Yield("id(vertex) AS vid, properties(vertex).name AS name").
+		FindInBatches(&players, 5, func(tx *DB, batch int) error {
+			for _, player := range players {
+				if err := tx.InsertEdge(batchFollow{SrcID: player.VID, DstID: "player_p0"}).Exec(); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		`LOOKUP ON player YIELD id(vertex) AS vid, properties(vertex).name AS name | LIMIT 5;`,
+		`INSERT EDGE follow(degree) VALUES "player_p1"->"player_p0":(0);`,
+	}, stmts)
+}
@@ -0,0 +1,25 @@
+package clause
+
+type ClearSpace struct {
+	IfExists  bool
+	SpaceName string
+}
+
+const ClearSpaceName = "CLEAR_SPACE"
+
+func (cs ClearSpace) Name() string {
+	return ClearSpaceName
+}
+
+func (cs ClearSpace) MergeIn(clause *Clause) {
+	clause.Expression = cs
+}
+
+func (cs ClearSpace) Build(nGQL Builder) error {
+	nGQL.WriteString("CLEAR SPACE ")
+	if cs.IfExists {
+		nGQL.WriteString("IF EXISTS ")
+	}
+	nGQL.WriteString(cs.SpaceName)
+	return nil
+}
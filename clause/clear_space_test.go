@@ -0,0 +1,29 @@
+package clause_test
+
+import (
+	"fmt"
+	"github.com/haysons/norm/clause"
+	"testing"
+)
+
+func TestClearSpace(t *testing.T) {
+	tests := []struct {
+		clauses []clause.Interface
+		gqlWant string
+		errWant error
+	}{
+		{
+			clauses: []clause.Interface{clause.ClearSpace{SpaceName: "test"}},
+			gqlWant: `CLEAR SPACE test`,
+		},
+		{
+			clauses: []clause.Interface{clause.ClearSpace{SpaceName: "test", IfExists: true}},
+			gqlWant: `CLEAR SPACE IF EXISTS test`,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("case #%d", i), func(t *testing.T) {
+			testBuildClauses(t, tt.clauses, tt.gqlWant, tt.errWant)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package clause
+
+import (
+	"fmt"
+	"github.com/haysons/norm/resolver"
+	"strconv"
+)
+
+// SpaceOptions the options of the graph space
+type SpaceOptions struct {
+	// PartitionNum the number of partitions, the default value of nebula graph is used if it is 0
+	PartitionNum int
+
+	// ReplicaFactor the number of replicas, the default value of nebula graph is used if it is 0
+	ReplicaFactor int
+
+	// VIDType the type of the vertex id, FIXED_STRING(FixedStringLength) for resolver.VIDTypeString,
+	// INT64 for resolver.VIDTypeInt64
+	VIDType resolver.VIDType
+
+	// FixedStringLength the length of the vertex id of string type
+	FixedStringLength int
+
+	// Comment the comment of the space
+	Comment string
+}
+
+type CreateSpace struct {
+	IfNotExists bool
+	SpaceName   string
+	Options     SpaceOptions
+}
+
+const CreateSpaceName = "CREATE_SPACE"
+
+func (cs CreateSpace) Name() string {
+	return CreateSpaceName
+}
+
+func (cs CreateSpace) MergeIn(clause *Clause) {
+	clause.Expression = cs
+}
+
+func (cs CreateSpace) Build(nGQL Builder) error {
+	if cs.SpaceName == "" {
+		return fmt.Errorf("norm: %w, build create space clause failed, space name empty", ErrInvalidClauseParams)
+	}
+	nGQL.WriteString("CREATE SPACE ")
+	if cs.IfNotExists {
+		nGQL.WriteString("IF NOT EXISTS ")
+	}
+	nGQL.WriteString(cs.SpaceName)
+	nGQL.WriteByte('(')
+	opts := cs.Options
+	if opts.PartitionNum > 0 {
+		nGQL.WriteString("partition_num = ")
+		nGQL.WriteString(strconv.Itoa(opts.PartitionNum))
+		nGQL.WriteString(", ")
+	}
+	if opts.ReplicaFactor > 0 {
+		nGQL.WriteString("replica_factor = ")
+		nGQL.WriteString(strconv.Itoa(opts.ReplicaFactor))
+		nGQL.WriteString(", ")
+	}
+	nGQL.WriteString("vid_type = ")
+	switch opts.VIDType {
+	case resolver.VIDTypeString:
+		if opts.FixedStringLength <= 0 {
+			return fmt.Errorf("norm: %w, build create space clause failed, fixed string length should be greater than 0", ErrInvalidClauseParams)
+		}
+		nGQL.WriteString("FIXED_STRING(")
+		nGQL.WriteString(strconv.Itoa(opts.FixedStringLength))
+		nGQL.WriteByte(')')
+	case resolver.VIDTypeInt64:
+		nGQL.WriteString("INT64")
+	default:
+		return fmt.Errorf("norm: %w, build create space clause failed, invalid vid type %d", ErrInvalidClauseParams, opts.VIDType)
+	}
+	nGQL.WriteByte(')')
+	if opts.Comment != "" {
+		nGQL.WriteString(" COMMENT = ")
+		nGQL.WriteString(strconv.Quote(opts.Comment))
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package clause
+
+import "fmt"
+
+type CreateSpaceAs struct {
+	IfNotExists bool
+	SpaceName   string
+	SourceSpace string
+}
+
+const CreateSpaceAsName = "CREATE_SPACE_AS"
+
+func (cs CreateSpaceAs) Name() string {
+	return CreateSpaceAsName
+}
+
+func (cs CreateSpaceAs) MergeIn(clause *Clause) {
+	clause.Expression = cs
+}
+
+func (cs CreateSpaceAs) Build(nGQL Builder) error {
+	if cs.SpaceName == "" || cs.SourceSpace == "" {
+		return fmt.Errorf("norm: %w, build create space as clause failed, space name empty", ErrInvalidClauseParams)
+	}
+	nGQL.WriteString("CREATE SPACE ")
+	if cs.IfNotExists {
+		nGQL.WriteString("IF NOT EXISTS ")
+	}
+	nGQL.WriteString(cs.SpaceName)
+	nGQL.WriteString(" AS ")
+	nGQL.WriteString(cs.SourceSpace)
+	return nil
+}
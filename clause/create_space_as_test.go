@@ -0,0 +1,33 @@
+package clause_test
+
+import (
+	"fmt"
+	"github.com/haysons/norm/clause"
+	"testing"
+)
+
+func TestCreateSpaceAs(t *testing.T) {
+	tests := []struct {
+		clauses []clause.Interface
+		gqlWant string
+		errWant error
+	}{
+		{
+			clauses: []clause.Interface{clause.CreateSpaceAs{SpaceName: "test_copy", SourceSpace: "test"}},
+			gqlWant: `CREATE SPACE test_copy AS test`,
+		},
+		{
+			clauses: []clause.Interface{clause.CreateSpaceAs{IfNotExists: true, SpaceName: "test_copy", SourceSpace: "test"}},
+			gqlWant: `CREATE SPACE IF NOT EXISTS test_copy AS test`,
+		},
+		{
+			clauses: []clause.Interface{clause.CreateSpaceAs{SpaceName: "test_copy"}},
+			errWant: clause.ErrInvalidClauseParams,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("case #%d", i), func(t *testing.T) {
+			testBuildClauses(t, tt.clauses, tt.gqlWant, tt.errWant)
+		})
+	}
+}
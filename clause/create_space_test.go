@@ -0,0 +1,52 @@
+package clause_test
+
+import (
+	"fmt"
+	"github.com/haysons/norm/clause"
+	"github.com/haysons/norm/resolver"
+	"testing"
+)
+
+func TestCreateSpace(t *testing.T) {
+	tests := []struct {
+		clauses []clause.Interface
+		gqlWant string
+		errWant error
+	}{
+		{
+			clauses: []clause.Interface{clause.CreateSpace{SpaceName: "test", Options: clause.SpaceOptions{VIDType: resolver.VIDTypeInt64}}},
+			gqlWant: `CREATE SPACE test(vid_type = INT64)`,
+		},
+		{
+			clauses: []clause.Interface{clause.CreateSpace{
+				IfNotExists: true,
+				SpaceName:   "basketballplayer",
+				Options: clause.SpaceOptions{
+					PartitionNum:      10,
+					ReplicaFactor:     1,
+					VIDType:           resolver.VIDTypeString,
+					FixedStringLength: 32,
+					Comment:           "basketball players",
+				},
+			}},
+			gqlWant: `CREATE SPACE IF NOT EXISTS basketballplayer(partition_num = 10, replica_factor = 1, vid_type = FIXED_STRING(32)) COMMENT = "basketball players"`,
+		},
+		{
+			clauses: []clause.Interface{clause.CreateSpace{SpaceName: "test", Options: clause.SpaceOptions{VIDType: resolver.VIDTypeString}}},
+			errWant: clause.ErrInvalidClauseParams,
+		},
+		{
+			clauses: []clause.Interface{clause.CreateSpace{SpaceName: "test"}},
+			errWant: clause.ErrInvalidClauseParams,
+		},
+		{
+			clauses: []clause.Interface{clause.CreateSpace{Options: clause.SpaceOptions{VIDType: resolver.VIDTypeInt64}}},
+			errWant: clause.ErrInvalidClauseParams,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("case #%d", i), func(t *testing.T) {
+			testBuildClauses(t, tt.clauses, tt.gqlWant, tt.errWant)
+		})
+	}
+}
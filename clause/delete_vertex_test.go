@@ -44,6 +44,22 @@ func TestDeleteVertex(t *testing.T) {
 			clauses: []clause.Interface{clause.DeleteVertex{VID: []*clause.Expr{{Str: "$-.id"}}, WithEdge: true}},
 			gqlWant: `DELETE VERTEX $-.id WITH EDGE`,
 		},
+		{
+			clauses: []clause.Interface{clause.DeleteVertex{VID: &t2{VID: "player100"}, WithEdge: true}},
+			gqlWant: `DELETE VERTEX "player100" WITH EDGE`,
+		},
+		{
+			clauses: []clause.Interface{clause.DeleteVertex{VID: []*v3{{VID: "v1"}, {VID: "v2"}}}},
+			gqlWant: `DELETE VERTEX "v1", "v2"`,
+		},
+		{
+			clauses: []clause.Interface{clause.DeleteVertex{VID: []*t2{nil}}},
+			errWant: clause.ErrInvalidClauseParams,
+		},
+		{
+			clauses: []clause.Interface{clause.DeleteVertex{VID: struct{}{}}},
+			errWant: clause.ErrInvalidClauseParams,
+		},
 		{
 			clauses: []clause.Interface{clause.DeleteVertex{}},
 			errWant: clause.ErrInvalidClauseParams,
@@ -18,22 +18,29 @@ type Builder interface {
 	WriteString(string) (int, error)
 }
 
+// ParamBuilder is a Builder that collects the values of expressions as query parameters, the values are sent to
+// the server along with the statement instead of being inlined into it.
+type ParamBuilder interface {
+	Builder
+
+	// AddParam registers the value as a query parameter and returns its placeholder in the statement, eg: $p1
+	AddParam(value any) (string, error)
+}
+
 // Expr raw expression
 type Expr struct {
 	Str  string
 	Vars []any
 }
 
-// Build raw expression
+// Build raw expression, if the builder is a ParamBuilder, the variables are built as query parameters
 func (expr Expr) Build(builder Builder) error {
 	var idx int
 	for _, v := range []byte(expr.Str) {
 		if v == '?' && len(expr.Vars) > idx {
-			valFmt, err := expr.formatValue(expr.Vars[idx])
-			if err != nil {
+			if err := expr.buildValue(builder, expr.Vars[idx]); err != nil {
 				return err
 			}
-			builder.WriteString(valFmt)
 			idx++
 		} else {
 			builder.WriteByte(v)
@@ -41,34 +48,33 @@ func (expr Expr) Build(builder Builder) error {
 	}
 	if idx < len(expr.Vars) {
 		for _, v := range expr.Vars[idx:] {
-			valFmt, err := expr.formatValue(v)
-			if err != nil {
+			if err := expr.buildValue(builder, v); err != nil {
 				return err
 			}
-			builder.WriteString(valFmt)
 		}
 	}
 	return nil
 }
 
-func (expr Expr) formatValue(value any) (string, error) {
+func (expr Expr) buildValue(builder Builder, value any) error {
 	switch v := value.(type) {
 	case Expr:
-		exprBuilder := new(strings.Builder)
-		err := v.Build(exprBuilder)
-		if err != nil {
-			return "", err
-		}
-		return exprBuilder.String(), nil
+		return v.Build(builder)
 	case *Expr:
-		exprBuilder := new(strings.Builder)
-		err := v.Build(exprBuilder)
+		return v.Build(builder)
+	default:
+		var valFmt string
+		var err error
+		if paramBuilder, ok := builder.(ParamBuilder); ok {
+			valFmt, err = paramBuilder.AddParam(value)
+		} else {
+			valFmt, err = resolver.FormatSimpleValue("", reflect.ValueOf(value))
+		}
 		if err != nil {
-			return "", err
+			return err
 		}
-		return exprBuilder.String(), nil
-	default:
-		return resolver.FormatSimpleValue("", reflect.ValueOf(value))
+		builder.WriteString(valFmt)
+		return nil
 	}
 }
 
@@ -126,7 +132,11 @@ func vertexIDExpr(vid any) (string, error) {
 			vidList = append(vidList, exprBuilder.String())
 		}
 	default:
-		return "", errors.New("vertex id must be a int, int64, string, clause.Expr, *clause.Expr or slice made of the above elements")
+		vertexVIDs, err := vertexesIDExpr(reflect.ValueOf(vid))
+		if err != nil {
+			return "", err
+		}
+		vidList = append(vidList, vertexVIDs...)
 	}
 	var vidExpr strings.Builder
 	for i, v := range vidList {
@@ -137,3 +147,33 @@ func vertexIDExpr(vid any) (string, error) {
 	}
 	return vidExpr.String(), nil
 }
+
+// vertexesIDExpr get the vid expressions of a vertex or a list of vertexes, the vertexes must be parseable
+func vertexesIDExpr(vertexes reflect.Value) ([]string, error) {
+	errInvalidVID := errors.New("vertex id must be a int, int64, string, clause.Expr, *clause.Expr, vertex or slice made of the above elements")
+	vertexes = reflect.Indirect(vertexes)
+	switch vertexes.Kind() {
+	case reflect.Struct:
+		vertexSchema, err := resolver.ParseVertex(vertexes.Type())
+		if err != nil {
+			return nil, errInvalidVID
+		}
+		return []string{vertexSchema.GetVIDExpr(vertexes)}, nil
+	case reflect.Slice, reflect.Array:
+		vertexSchema, err := resolver.ParseVertex(vertexes.Type().Elem())
+		if err != nil {
+			return nil, errInvalidVID
+		}
+		vidList := make([]string, 0, vertexes.Len())
+		for i := 0; i < vertexes.Len(); i++ {
+			vertex := reflect.Indirect(vertexes.Index(i))
+			if !vertex.IsValid() {
+				return nil, errInvalidVID
+			}
+			vidList = append(vidList, vertexSchema.GetVIDExpr(vertex))
+		}
+		return vidList, nil
+	default:
+		return nil, errInvalidVID
+	}
+}
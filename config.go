@@ -36,6 +36,21 @@ type Config struct {
 	// you need to change it to the same configuration as the nebula graph server.
 	TimezoneName string `json:"timezone_name" yaml:"timezone_name"`
 
+	// ParameterizedQuery when enabled, the arguments of Where, When, Raw and other expressions are sent to the
+	// server as query parameters ($p1, $p2...) instead of being inlined into the statement
+	ParameterizedQuery bool `json:"parameterized_query" yaml:"parameterized_query"`
+
+	// Replicas read replica clusters, read-only statements are routed to them in turn, and the other statements
+	// are executed on the primary cluster of Addresses
+	Replicas []ReplicaConfig `json:"replicas" yaml:"replicas"`
+
+	// DryRun when enabled, statements are built, logged and passed through the plugins, but not executed, an empty
+	// result is returned instead. it is used to review the statements, eg: the ones generated by Migrator.
+	DryRun bool `json:"dry_run" yaml:"dry_run"`
+
+	// Retry the retry policy of the statements that failed because of transient failures, nil means no retry
+	Retry *RetryPolicy `json:"retry" yaml:"retry"`
+
 	// nebulaSessionOpts nebula session pool config
 	nebulaSessionOpts []nebula.SessionPoolConfOption
 
@@ -44,6 +59,18 @@ type Config struct {
 	logger logger.Interface
 }
 
+// ReplicaConfig for a read replica cluster
+type ReplicaConfig struct {
+	// Addresses server address list of the replica cluster，host:port
+	Addresses []string `json:"addresses" yaml:"addresses"`
+
+	// Username to connect to the replica cluster, default is the Username of Config
+	Username string `json:"username" yaml:"username"`
+
+	// Password to connect to the replica cluster, default is the Password of Config
+	Password string `json:"password" yaml:"password"`
+}
+
 type ConfigOption interface {
 	apply(*Config)
 }
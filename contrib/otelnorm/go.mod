@@ -0,0 +1,27 @@
+module github.com/haysons/norm/contrib/otelnorm
+
+go 1.18
+
+// develop against the local norm until a release of norm provides the APIs used here, then require the release
+replace github.com/haysons/norm => ../..
+
+require (
+	github.com/haysons/norm v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+	github.com/vesoft-inc/nebula-go/v3 v3.8.1-0.20250117054948-5312ccfebe2f
+	go.opentelemetry.io/otel v1.14.0
+	go.opentelemetry.io/otel/sdk v1.14.0
+	go.opentelemetry.io/otel/trace v1.14.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-logr/logr v1.2.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/vesoft-inc/fbthrift v0.0.0-20230214024353-fa2f34755b28 // indirect
+	golang.org/x/net v0.33.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,145 @@
+// Package otelnorm traces the statements executed by norm with OpenTelemetry.
+//
+//	db, err := norm.Open(conf)
+//	if err != nil {
+//		return err
+//	}
+//	if err = db.Use(otelnorm.New()); err != nil {
+//		return err
+//	}
+package otelnorm
+
+import (
+	"context"
+	"errors"
+	"github.com/haysons/norm"
+	"github.com/haysons/norm/statement"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"strconv"
+)
+
+const (
+	// ScopeName the instrumentation scope name of the tracer
+	ScopeName = "github.com/haysons/norm/contrib/otelnorm"
+
+	dbSystem = "nebulagraph"
+)
+
+// attribute keys of the spans, following the semantic conventions of database client spans
+const (
+	AttrDBSystem     = attribute.Key("db.system")
+	AttrDBNamespace  = attribute.Key("db.namespace")
+	AttrDBOperation  = attribute.Key("db.operation.name")
+	AttrDBQueryText  = attribute.Key("db.query.text")
+	AttrDBRows       = attribute.Key("db.response.returned_rows")
+	AttrDBStatusCode = attribute.Key("db.response.status_code")
+)
+
+// Plugin is a norm plugin which wraps every execution of statements in a span
+type Plugin struct {
+	tracer    trace.Tracer
+	withNGQL  bool
+	sanitizer func(nGQL string) string
+	attrs     []attribute.KeyValue
+}
+
+// Option configures the Plugin
+type Option func(p *Plugin)
+
+// WithTracerProvider specifies the tracer provider, default is the global tracer provider
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(p *Plugin) {
+		p.tracer = provider.Tracer(ScopeName)
+	}
+}
+
+// WithoutNGQL excludes the nGQL from the spans
+func WithoutNGQL() Option {
+	return func(p *Plugin) {
+		p.withNGQL = false
+	}
+}
+
+// WithSanitizer sanitizes the nGQL before it is recorded in the spans, eg: SanitizeLiterals
+func WithSanitizer(sanitizer func(nGQL string) string) Option {
+	return func(p *Plugin) {
+		p.sanitizer = sanitizer
+	}
+}
+
+// WithAttributes adds extra attributes to all spans
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(p *Plugin) {
+		p.attrs = append(p.attrs, attrs...)
+	}
+}
+
+// New creates the plugin, which should be registered by norm.DB.Use
+func New(opts ...Option) *Plugin {
+	p := &Plugin{
+		tracer:   otel.GetTracerProvider().Tracer(ScopeName),
+		withNGQL: true,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+func (p *Plugin) Name() string {
+	return "otelnorm"
+}
+
+func (p *Plugin) Intercept(next norm.Handler) norm.Handler {
+	return func(ctx context.Context, query *norm.Query) (*nebula.ResultSet, error) {
+		kind := statement.Kind(query.NGQL)
+		attrs := make([]attribute.KeyValue, 0, len(p.attrs)+4)
+		attrs = append(attrs, AttrDBSystem.String(dbSystem), AttrDBOperation.String(kind))
+		if query.Space != "" {
+			attrs = append(attrs, AttrDBNamespace.String(query.Space))
+		}
+		if p.withNGQL {
+			nGQL := query.NGQL
+			if p.sanitizer != nil {
+				nGQL = p.sanitizer(nGQL)
+			}
+			attrs = append(attrs, AttrDBQueryText.String(nGQL))
+		}
+		attrs = append(attrs, p.attrs...)
+
+		ctx, span := p.tracer.Start(ctx, spanName(kind, query.Space),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		res, err := next(ctx, query)
+		if res != nil {
+			span.SetAttributes(AttrDBRows.Int(res.GetRowSize()))
+		}
+		if err != nil {
+			var normErr *norm.Error
+			if errors.As(err, &normErr) {
+				span.SetAttributes(AttrDBStatusCode.String(strconv.FormatInt(int64(normErr.Code), 10)))
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		return res, err
+	}
+}
+
+// spanName returns the name of the span, eg: GO basketballplayer
+func spanName(kind, space string) string {
+	if kind == "" {
+		kind = "nGQL"
+	}
+	if space == "" {
+		return kind
+	}
+	return kind + " " + space
+}
@@ -0,0 +1,154 @@
+package otelnorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/haysons/norm"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"testing"
+)
+
+func newResult(t *testing.T, code nebulaType.ErrorCode, rows int) *nebula.ResultSet {
+	data := &nebulaType.DataSet{ColumnNames: [][]byte{[]byte("id")}}
+	for i := 0; i < rows; i++ {
+		data.Rows = append(data.Rows, &nebulaType.Row{Values: []*nebulaType.Value{{SVal: []byte(fmt.Sprintf("player%d", i))}}})
+	}
+	res, err := nebula.GenResultSet(&graph.ExecutionResponse{ErrorCode: code, Data: data})
+	assert.NoError(t, err)
+	return res
+}
+
+func TestPlugin(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	tracer := provider.Tracer("test")
+
+	tests := []struct {
+		opts      []Option
+		query     *norm.Query
+		res       *nebula.ResultSet
+		err       error
+		nameWant  string
+		attrsWant []attribute.KeyValue
+		errWant   bool
+	}{
+		{
+			query:    &norm.Query{NGQL: `GO FROM "player102" OVER serve YIELD dst(edge) AS id;`, Space: "basketballplayer"},
+			res:      newResult(t, nebulaType.ErrorCode_SUCCEEDED, 2),
+			nameWant: "GO basketballplayer",
+			attrsWant: []attribute.KeyValue{
+				AttrDBSystem.String("nebulagraph"),
+				AttrDBOperation.String("GO"),
+				AttrDBNamespace.String("basketballplayer"),
+				AttrDBQueryText.String(`GO FROM "player102" OVER serve YIELD dst(edge) AS id;`),
+				AttrDBRows.Int(2),
+			},
+		},
+		{
+			opts:     []Option{WithSanitizer(SanitizeLiterals), WithAttributes(attribute.String("service", "test"))},
+			query:    &norm.Query{NGQL: `INSERT VERTEX player(name, age) VALUES "player100":("Tim Duncan", 42);`},
+			res:      newResult(t, nebulaType.ErrorCode_SUCCEEDED, 0),
+			nameWant: "INSERT VERTEX",
+			attrsWant: []attribute.KeyValue{
+				AttrDBSystem.String("nebulagraph"),
+				AttrDBOperation.String("INSERT VERTEX"),
+				AttrDBQueryText.String(`INSERT VERTEX player(name, age) VALUES ?:(?, ?);`),
+				attribute.String("service", "test"),
+				AttrDBRows.Int(0),
+			},
+		},
+		{
+			opts:     []Option{WithoutNGQL()},
+			query:    &norm.Query{NGQL: `FETCH PROP ON player "player100" YIELD vertex AS v;`, Space: "basketballplayer"},
+			res:      newResult(t, nebulaType.ErrorCode_E_SEMANTIC_ERROR, 0),
+			err:      &norm.Error{Code: nebula.ErrorCode_E_SEMANTIC_ERROR, Msg: "TagNotFound"},
+			nameWant: "FETCH basketballplayer",
+			attrsWant: []attribute.KeyValue{
+				AttrDBSystem.String("nebulagraph"),
+				AttrDBOperation.String("FETCH"),
+				AttrDBNamespace.String("basketballplayer"),
+				AttrDBRows.Int(0),
+				AttrDBStatusCode.String("-1009"),
+			},
+			errWant: true,
+		},
+		{
+			query:    &norm.Query{NGQL: `YIELD 1;`},
+			err:      errors.New("failed to get session"),
+			nameWant: "YIELD",
+			attrsWant: []attribute.KeyValue{
+				AttrDBSystem.String("nebulagraph"),
+				AttrDBOperation.String("YIELD"),
+				AttrDBQueryText.String(`YIELD 1;`),
+			},
+			errWant: true,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("case #%d", i), func(t *testing.T) {
+			exporter.Reset()
+			ctx, parent := tracer.Start(context.Background(), "parent")
+			plugin := New(append([]Option{WithTracerProvider(provider)}, tt.opts...)...)
+			handler := plugin.Intercept(func(ctx context.Context, query *norm.Query) (*nebula.ResultSet, error) {
+				// the span is propagated to the next handler
+				assert.True(t, trace.SpanContextFromContext(ctx).IsValid())
+				assert.NotEqual(t, parent.SpanContext().SpanID(), trace.SpanContextFromContext(ctx).SpanID())
+				return tt.res, tt.err
+			})
+			res, err := handler(ctx, tt.query)
+			parent.End()
+			assert.Equal(t, tt.res, res)
+			assert.Equal(t, tt.err, err)
+
+			spans := exporter.GetSpans()
+			if !assert.Len(t, spans, 2) {
+				return
+			}
+			span := spans[0]
+			assert.Equal(t, tt.nameWant, span.Name)
+			assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+			assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+			assert.Equal(t, tt.attrsWant, span.Attributes)
+			if tt.errWant {
+				assert.Equal(t, codes.Error, span.Status.Code)
+				assert.Len(t, span.Events, 1)
+			} else {
+				assert.Equal(t, codes.Unset, span.Status.Code)
+			}
+		})
+	}
+}
+
+func TestSanitizeLiterals(t *testing.T) {
+	tests := []struct {
+		nGQL string
+		want string
+	}{
+		{
+			nGQL: `FETCH PROP ON player "player100" YIELD vertex AS v;`,
+			want: `FETCH PROP ON player ? YIELD vertex AS v;`,
+		},
+		{
+			nGQL: `GO 2 STEPS FROM "player\"100" OVER follow WHERE $$.player.age > 30.5 AND properties(edge).degree == 90 YIELD dst(edge);`,
+			want: `GO ? STEPS FROM ? OVER follow WHERE $$.player.age > ? AND properties(edge).degree == ? YIELD dst(edge);`,
+		},
+		{
+			nGQL: `LOOKUP ON player2 WHERE player2.name == 'Tim' YIELD id(vertex) | LIMIT 10, 20;`,
+			want: `LOOKUP ON player2 WHERE player2.name == ? YIELD id(vertex) | LIMIT ?, ?;`,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("case #%d", i), func(t *testing.T) {
+			assert.Equal(t, tt.want, SanitizeLiterals(tt.nGQL))
+		})
+	}
+}
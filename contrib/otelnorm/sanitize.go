@@ -0,0 +1,40 @@
+package otelnorm
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SanitizeLiterals replaces the string and number literals of the nGQL with '?', so that the values are not
+// recorded in the spans, eg: FETCH PROP ON player "player100" YIELD vertex AS v => FETCH PROP ON player ? YIELD vertex AS v
+func SanitizeLiterals(nGQL string) string {
+	var b strings.Builder
+	b.Grow(len(nGQL))
+	runes := []rune(nGQL)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '"' || r == '\'':
+			// skip to the closing quote
+			for i++; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+			b.WriteByte('?')
+		case unicode.IsDigit(r) && (i == 0 || !isIdentRune(runes[i-1])):
+			for i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.') {
+				i++
+			}
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// isIdentRune reports whether r may be part of an identifier, eg: player100, $-.age
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '$' || r == '.'
+}
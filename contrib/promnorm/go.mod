@@ -0,0 +1,32 @@
+module github.com/haysons/norm/contrib/promnorm
+
+go 1.18
+
+// develop against the local norm until a release of norm provides the APIs used here, then require the release
+replace github.com/haysons/norm => ../..
+
+require (
+	github.com/haysons/norm v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_golang v1.16.0
+	github.com/stretchr/testify v1.10.0
+	github.com/vesoft-inc/nebula-go/v3 v3.8.1-0.20250117054948-5312ccfebe2f
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/kr/text v0.2.0 // indirect
+	github.com/matttproud/golang_protobuf_extensions v1.0.4 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/prometheus/client_model v0.3.0 // indirect
+	github.com/prometheus/common v0.42.0 // indirect
+	github.com/prometheus/procfs v0.10.1 // indirect
+	github.com/vesoft-inc/fbthrift v0.0.0-20230214024353-fa2f34755b28 // indirect
+	golang.org/x/net v0.33.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/protobuf v1.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
@@ -0,0 +1,164 @@
+// Package promnorm collects the metrics of the statements executed by norm and the session pools with Prometheus.
+//
+//	plugin := promnorm.New(db)
+//	if err := db.Use(plugin); err != nil {
+//		return err
+//	}
+//	prometheus.MustRegister(plugin)
+package promnorm
+
+import (
+	"context"
+	"errors"
+	"github.com/haysons/norm"
+	"github.com/haysons/norm/statement"
+	"github.com/prometheus/client_golang/prometheus"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"strconv"
+	"time"
+)
+
+// Plugin is a norm plugin which collects the metrics of statements, it is also a prometheus.Collector which
+// should be registered to the prometheus registry. the metrics are:
+//   - norm_statement_duration_seconds: histogram of the statement latency by kind and space
+//   - norm_statement_errors_total: counter of the failed statements by kind, space and error code
+//   - norm_statement_rows_total: counter of the rows returned by kind and space
+//   - norm_sessions_total, norm_sessions_in_use, norm_sessions_idle: gauges of the session pools
+type Plugin struct {
+	db       *norm.DB
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+	rows     *prometheus.CounterVec
+	sessions *prometheus.Desc
+	inUse    *prometheus.Desc
+	idle     *prometheus.Desc
+}
+
+type options struct {
+	namespace   string
+	buckets     []float64
+	constLabels prometheus.Labels
+}
+
+// Option configures the Plugin
+type Option func(o *options)
+
+// WithNamespace specifies the namespace of the metrics, eg: with namespace 'app' the metrics are named as
+// app_norm_statement_duration_seconds
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithBuckets specifies the buckets of the latency histogram, default is prometheus.DefBuckets
+func WithBuckets(buckets []float64) Option {
+	return func(o *options) {
+		o.buckets = buckets
+	}
+}
+
+// WithConstLabels adds constant labels to all metrics, eg: the name of the cluster
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(o *options) {
+		o.constLabels = labels
+	}
+}
+
+// New creates the plugin, the gauges of the session pools are collected from db, they are omitted if db is nil
+func New(db *norm.DB, opts ...Option) *Plugin {
+	o := &options{buckets: prometheus.DefBuckets}
+	for _, opt := range opts {
+		opt(o)
+	}
+	labels := []string{"kind", "space"}
+	return &Plugin{
+		db: db,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   o.namespace,
+			Subsystem:   "norm",
+			Name:        "statement_duration_seconds",
+			Help:        "Latency of the statements executed by norm.",
+			Buckets:     o.buckets,
+			ConstLabels: o.constLabels,
+		}, labels),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   o.namespace,
+			Subsystem:   "norm",
+			Name:        "statement_errors_total",
+			Help:        "Number of the failed statements by nebula graph error code.",
+			ConstLabels: o.constLabels,
+		}, append(labels, "code")),
+		rows: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   o.namespace,
+			Subsystem:   "norm",
+			Name:        "statement_rows_total",
+			Help:        "Number of the rows returned by the statements.",
+			ConstLabels: o.constLabels,
+		}, labels),
+		sessions: prometheus.NewDesc(prometheus.BuildFQName(o.namespace, "norm", "sessions_total"),
+			"Number of the sessions created by the session pools.", nil, o.constLabels),
+		inUse: prometheus.NewDesc(prometheus.BuildFQName(o.namespace, "norm", "sessions_in_use"),
+			"Number of the sessions executing statements.", nil, o.constLabels),
+		idle: prometheus.NewDesc(prometheus.BuildFQName(o.namespace, "norm", "sessions_idle"),
+			"Number of the idle sessions.", nil, o.constLabels),
+	}
+}
+
+func (p *Plugin) Name() string {
+	return "promnorm"
+}
+
+func (p *Plugin) Intercept(next norm.Handler) norm.Handler {
+	return func(ctx context.Context, query *norm.Query) (*nebula.ResultSet, error) {
+		begin := time.Now()
+		res, err := next(ctx, query)
+		kind := statement.Kind(query.NGQL)
+		p.duration.WithLabelValues(kind, query.Space).Observe(time.Since(begin).Seconds())
+		if res != nil {
+			p.rows.WithLabelValues(kind, query.Space).Add(float64(res.GetRowSize()))
+		}
+		if err != nil {
+			p.errors.WithLabelValues(kind, query.Space, errorCode(err)).Inc()
+		}
+		return res, err
+	}
+}
+
+// errorCode returns the label of the error, which is the nebula graph error code if present
+func errorCode(err error) string {
+	var normErr *norm.Error
+	switch {
+	case errors.As(err, &normErr):
+		return strconv.FormatInt(int64(normErr.Code), 10)
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	default:
+		return "unknown"
+	}
+}
+
+func (p *Plugin) Describe(ch chan<- *prometheus.Desc) {
+	p.duration.Describe(ch)
+	p.errors.Describe(ch)
+	p.rows.Describe(ch)
+	if p.db != nil {
+		ch <- p.sessions
+		ch <- p.inUse
+		ch <- p.idle
+	}
+}
+
+func (p *Plugin) Collect(ch chan<- prometheus.Metric) {
+	p.duration.Collect(ch)
+	p.errors.Collect(ch)
+	p.rows.Collect(ch)
+	if p.db != nil {
+		stats := p.db.Stats()
+		ch <- prometheus.MustNewConstMetric(p.sessions, prometheus.GaugeValue, float64(stats.TotalSessions))
+		ch <- prometheus.MustNewConstMetric(p.inUse, prometheus.GaugeValue, float64(stats.InUse))
+		ch <- prometheus.MustNewConstMetric(p.idle, prometheus.GaugeValue, float64(stats.Idle))
+	}
+}
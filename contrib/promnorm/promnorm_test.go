@@ -0,0 +1,70 @@
+package promnorm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/haysons/norm"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"strings"
+	"testing"
+)
+
+func newResult(t *testing.T, code nebulaType.ErrorCode, rows int) *nebula.ResultSet {
+	data := &nebulaType.DataSet{ColumnNames: [][]byte{[]byte("id")}}
+	for i := 0; i < rows; i++ {
+		data.Rows = append(data.Rows, &nebulaType.Row{Values: []*nebulaType.Value{{SVal: []byte(fmt.Sprintf("player%d", i))}}})
+	}
+	res, err := nebula.GenResultSet(&graph.ExecutionResponse{ErrorCode: code, Data: data})
+	assert.NoError(t, err)
+	return res
+}
+
+func TestPlugin(t *testing.T) {
+	plugin := New(&norm.DB{}, WithNamespace("app"))
+	ctx := context.Background()
+	execute := func(query *norm.Query, res *nebula.ResultSet, err error) {
+		handler := plugin.Intercept(func(ctx context.Context, query *norm.Query) (*nebula.ResultSet, error) {
+			return res, err
+		})
+		_, _ = handler(ctx, query)
+	}
+
+	goQuery := &norm.Query{NGQL: `GO FROM "player102" OVER serve YIELD dst(edge) AS id;`, Space: "basketballplayer"}
+	execute(goQuery, newResult(t, nebulaType.ErrorCode_SUCCEEDED, 3), nil)
+	execute(goQuery, newResult(t, nebulaType.ErrorCode_SUCCEEDED, 2), nil)
+	fetchQuery := &norm.Query{NGQL: `FETCH PROP ON player "player100" YIELD vertex AS v;`, Space: "basketballplayer"}
+	execute(fetchQuery, newResult(t, nebulaType.ErrorCode_E_SEMANTIC_ERROR, 0),
+		&norm.Error{Code: nebula.ErrorCode_E_SEMANTIC_ERROR, Msg: "TagNotFound"})
+	execute(fetchQuery, nil, fmt.Errorf("norm: %w", context.DeadlineExceeded))
+	execute(fetchQuery, nil, errors.New("failed to get session"))
+
+	assert.Equal(t, 5.0, testutil.ToFloat64(plugin.rows.WithLabelValues("GO", "basketballplayer")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(plugin.rows.WithLabelValues("FETCH", "basketballplayer")))
+	assert.Equal(t, 2, testutil.CollectAndCount(plugin.duration))
+
+	err := testutil.CollectAndCompare(plugin, strings.NewReader(`
+# HELP app_norm_statement_errors_total Number of the failed statements by nebula graph error code.
+# TYPE app_norm_statement_errors_total counter
+app_norm_statement_errors_total{code="-1009",kind="FETCH",space="basketballplayer"} 1
+app_norm_statement_errors_total{code="deadline_exceeded",kind="FETCH",space="basketballplayer"} 1
+app_norm_statement_errors_total{code="unknown",kind="FETCH",space="basketballplayer"} 1
+# HELP app_norm_sessions_idle Number of the idle sessions.
+# TYPE app_norm_sessions_idle gauge
+app_norm_sessions_idle 0
+# HELP app_norm_sessions_in_use Number of the sessions executing statements.
+# TYPE app_norm_sessions_in_use gauge
+app_norm_sessions_in_use 0
+# HELP app_norm_sessions_total Number of the sessions created by the session pools.
+# TYPE app_norm_sessions_total gauge
+app_norm_sessions_total 0
+`), "app_norm_statement_errors_total", "app_norm_sessions_total", "app_norm_sessions_in_use", "app_norm_sessions_idle")
+	assert.NoError(t, err)
+
+	// the gauges of the session pools are omitted without db
+	assert.Equal(t, 0, testutil.CollectAndCount(New(nil)))
+}
@@ -2,8 +2,11 @@ package norm
 
 import (
 	"errors"
+	"fmt"
 	"github.com/haysons/norm/clause"
 	"github.com/haysons/norm/resolver"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
 )
 
 var (
@@ -18,4 +21,119 @@ var (
 
 	// ErrInvalidClauseParams usually because the arguments to the build clause are anomalous, causing the build to fail
 	ErrInvalidClauseParams = clause.ErrInvalidClauseParams
+
+	// ErrPluginRegistered a plugin with the same name has already been registered
+	ErrPluginRegistered = errors.New("plugin already registered")
+
+	// ErrJobFailed the job of nebula graph failed or was stopped, eg: the job rebuilding indexes
+	ErrJobFailed = errors.New("job failed")
+
+	// ErrMigrationLocked the versioned migrations are being applied by another Migrator
+	ErrMigrationLocked = errors.New("migration locked")
 )
+
+// Error is returned when nebula graph fails to execute the statement, it carries the error code returned by the
+// server, so that the failures can be classified without parsing the error message.
+//
+//	var normErr *norm.Error
+//	if errors.As(err, &normErr) && normErr.Code == nebula.ErrorCode_E_SEMANTIC_ERROR {
+//		// ...
+//	}
+type Error struct {
+	// Code the error code returned by nebula graph
+	Code nebula.ErrorCode
+
+	// Msg the error message returned by nebula graph
+	Msg string
+
+	// NGQL the statement that failed, it is empty if the error is returned by Scan or Pluck
+	NGQL string
+}
+
+func (e *Error) Error() string {
+	if e.NGQL == "" {
+		return fmt.Sprintf("norm: result is not succeed, err code: %d, msg: %s", e.Code, e.Msg)
+	}
+	return fmt.Sprintf("norm: result is not succeed, err code: %d, msg: %s, nGQL: %s", e.Code, e.Msg, e.NGQL)
+}
+
+// Is reports whether the target is an *Error with the same error code, eg:
+// errors.Is(err, &norm.Error{Code: nebula.ErrorCode_E_SYNTAX_ERROR})
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code
+}
+
+// newResultError creates the error of the failed result, nil is returned if the result is succeeded
+func newResultError(res *nebula.ResultSet, nGQL string) error {
+	if res == nil || res.IsSucceed() {
+		return nil
+	}
+	return &Error{Code: res.GetErrorCode(), Msg: res.GetErrorMsg(), NGQL: nGQL}
+}
+
+// notFoundCodes the error codes returned when the space, schema or data operated on does not exist
+var notFoundCodes = map[nebula.ErrorCode]bool{
+	nebula.ErrorCode(nebulaType.ErrorCode_E_SPACE_NOT_FOUND):     true,
+	nebula.ErrorCode(nebulaType.ErrorCode_E_TAG_NOT_FOUND):       true,
+	nebula.ErrorCode(nebulaType.ErrorCode_E_EDGE_NOT_FOUND):      true,
+	nebula.ErrorCode(nebulaType.ErrorCode_E_INDEX_NOT_FOUND):     true,
+	nebula.ErrorCode(nebulaType.ErrorCode_E_EDGE_PROP_NOT_FOUND): true,
+	nebula.ErrorCode(nebulaType.ErrorCode_E_TAG_PROP_NOT_FOUND):  true,
+	nebula.ErrorCode(nebulaType.ErrorCode_E_KEY_NOT_FOUND):       true,
+	nebula.ErrorCode(nebulaType.ErrorCode_E_PART_NOT_FOUND):      true,
+	nebula.ErrorCode_E_USER_NOT_FOUND:                            true,
+}
+
+// retryableCodes the error codes of transient failures, the statement may succeed if it is executed again
+var retryableCodes = map[nebula.ErrorCode]bool{
+	nebula.ErrorCode_E_DISCONNECTED:                               true,
+	nebula.ErrorCode_E_FAIL_TO_CONNECT:                            true,
+	nebula.ErrorCode_E_RPC_FAILURE:                                true,
+	nebula.ErrorCode_E_SESSION_INVALID:                            true,
+	nebula.ErrorCode_E_SESSION_TIMEOUT:                            true,
+	nebula.ErrorCode(nebulaType.ErrorCode_E_LEADER_CHANGED):       true,
+	nebula.ErrorCode(nebulaType.ErrorCode_E_TOO_MANY_CONNECTIONS): true,
+	nebula.ErrorCode(nebulaType.ErrorCode_E_WRITE_STALLED):        true,
+}
+
+// errorCode returns the error code carried by err, ok is false if err is not caused by a failed result
+func errorCode(err error) (code nebula.ErrorCode, ok bool) {
+	var normErr *Error
+	if !errors.As(err, &normErr) {
+		return 0, false
+	}
+	return normErr.Code, true
+}
+
+// IsSyntaxError reports whether the statement failed because of a syntax error
+func IsSyntaxError(err error) bool {
+	code, ok := errorCode(err)
+	return ok && code == nebula.ErrorCode_E_SYNTAX_ERROR
+}
+
+// IsSemanticError reports whether the statement failed because of a semantic error, eg: the tag or the prop used
+// in the statement is not defined
+func IsSemanticError(err error) bool {
+	code, ok := errorCode(err)
+	return ok && code == nebula.ErrorCode_E_SEMANTIC_ERROR
+}
+
+// IsNotFound reports whether the record queried is not found, or the space, schema or data operated on does not exist
+func IsNotFound(err error) bool {
+	if errors.Is(err, ErrRecordNotFound) {
+		return true
+	}
+	code, ok := errorCode(err)
+	return ok && notFoundCodes[code]
+}
+
+// IsRetryable reports whether the statement failed because of a transient failure, eg: the leader changed or
+// the connection was broken, executing the statement again may succeed
+func IsRetryable(err error) bool {
+	code, ok := errorCode(err)
+	return ok && retryableCodes[code]
+}
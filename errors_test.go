@@ -0,0 +1,103 @@
+package norm
+
+import (
+	"errors"
+	"fmt"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"testing"
+)
+
+func newFailedResult(t *testing.T, code nebulaType.ErrorCode, msg string) *nebula.ResultSet {
+	res, err := nebula.GenResultSet(&graph.ExecutionResponse{ErrorCode: code, ErrorMsg: []byte(msg)})
+	assert.NoError(t, err)
+	return res
+}
+
+func TestError(t *testing.T) {
+	res := newFailedResult(t, nebulaType.ErrorCode_E_SYNTAX_ERROR, "syntax error near `GO'")
+	err := newResultError(res, "GO FORM")
+	assert.EqualError(t, err, "norm: result is not succeed, err code: -1004, msg: syntax error near `GO', nGQL: GO FORM")
+
+	wrapped := fmt.Errorf("query players failed: %w", err)
+	var normErr *Error
+	assert.True(t, errors.As(wrapped, &normErr))
+	assert.Equal(t, nebula.ErrorCode_E_SYNTAX_ERROR, normErr.Code)
+	assert.Equal(t, "GO FORM", normErr.NGQL)
+	assert.True(t, errors.Is(wrapped, &Error{Code: nebula.ErrorCode_E_SYNTAX_ERROR}))
+	assert.False(t, errors.Is(wrapped, &Error{Code: nebula.ErrorCode_E_SEMANTIC_ERROR}))
+
+	assert.EqualError(t, Scan(res, new(map[string]any)), "norm: result is not succeed, err code: -1004, msg: syntax error near `GO'")
+
+	succeeded, err := nebula.GenResultSet(&graph.ExecutionResponse{})
+	assert.NoError(t, err)
+	assert.NoError(t, newResultError(succeeded, "YIELD 1"))
+}
+
+func TestRawResultError(t *testing.T) {
+	executor := &fakeExecutor{res: newFailedResult(t, nebulaType.ErrorCode_E_SYNTAX_ERROR, "syntax error near `GO'")}
+	db, err := OpenWithExecutor(&Config{}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+
+	// the failed result is returned without error by RawResult, and with an *Error by the others
+	res, err := db.Raw("GO FORM").RawResult()
+	assert.NoError(t, err)
+	assert.False(t, res.IsSucceed())
+	assert.Equal(t, nebula.ErrorCode_E_SYNTAX_ERROR, res.GetErrorCode())
+	assert.True(t, errors.Is(db.Raw("GO FORM").Exec(), &Error{Code: nebula.ErrorCode_E_SYNTAX_ERROR}))
+}
+
+func TestErrorPredicates(t *testing.T) {
+	tests := []struct {
+		err       error
+		syntax    bool
+		semantic  bool
+		notFound  bool
+		retryable bool
+	}{
+		{
+			err:    newResultError(newFailedResult(t, nebulaType.ErrorCode_E_SYNTAX_ERROR, ""), ""),
+			syntax: true,
+		},
+		{
+			err:      fmt.Errorf("wrapped: %w", newResultError(newFailedResult(t, nebulaType.ErrorCode_E_SEMANTIC_ERROR, ""), "")),
+			semantic: true,
+		},
+		{
+			err:      newResultError(newFailedResult(t, nebulaType.ErrorCode_E_SPACE_NOT_FOUND, ""), ""),
+			notFound: true,
+		},
+		{
+			err:      fmt.Errorf("norm: %w", ErrRecordNotFound),
+			notFound: true,
+		},
+		{
+			err:       newResultError(newFailedResult(t, nebulaType.ErrorCode_E_LEADER_CHANGED, ""), ""),
+			retryable: true,
+		},
+		{
+			err:       newResultError(newFailedResult(t, nebulaType.ErrorCode_E_RPC_FAILURE, ""), ""),
+			retryable: true,
+		},
+		{
+			err: newResultError(newFailedResult(t, nebulaType.ErrorCode_E_EXECUTION_ERROR, ""), ""),
+		},
+		{
+			err: errors.New("unknown error"),
+		},
+		{
+			err: nil,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("case #%d", i), func(t *testing.T) {
+			assert.Equal(t, tt.syntax, IsSyntaxError(tt.err))
+			assert.Equal(t, tt.semantic, IsSemanticError(tt.err))
+			assert.Equal(t, tt.notFound, IsNotFound(tt.err))
+			assert.Equal(t, tt.retryable, IsRetryable(tt.err))
+		})
+	}
+}
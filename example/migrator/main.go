@@ -61,7 +61,8 @@ func (t *WomanUpdate) VertexTagName() string {
 }
 
 func migrateTags() {
-	migrator := db.Debug().Migrator()
+	// wait for the created indexes to be ready and rebuild them within AutoMigrateVertexes
+	migrator := db.Debug().Migrator(norm.WithRebuildIndexes())
 	hasTag, err := migrator.HasVertexTag("woman")
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,21 @@
+package norm
+
+import (
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// Executor executes the nGQL statements built by DB, nebula.SessionPool is the default implementation used by Open.
+// A custom implementation can be passed to OpenWithExecutor, eg: a fake for testing, or a router across multiple
+// clusters. Executor must be safe for concurrent use.
+type Executor interface {
+	// Execute executes the nGQL statement
+	Execute(stmt string) (*nebula.ResultSet, error)
+
+	// ExecuteWithParameter executes the nGQL statement with query parameters
+	ExecuteWithParameter(stmt string, params map[string]any) (*nebula.ResultSet, error)
+
+	// Close releases the resources held by the executor
+	Close()
+}
+
+var _ Executor = (*nebula.SessionPool)(nil)
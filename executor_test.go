@@ -0,0 +1,122 @@
+package norm
+
+import (
+	"errors"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"testing"
+	"time"
+)
+
+// fakeExecutor records the statements and returns the same result for all of them, an empty succeeded result if
+// res is nil
+type fakeExecutor struct {
+	res    *nebula.ResultSet
+	stmts  []string
+	closed bool
+}
+
+func (e *fakeExecutor) Execute(stmt string) (*nebula.ResultSet, error) {
+	return e.ExecuteWithParameter(stmt, nil)
+}
+
+func (e *fakeExecutor) ExecuteWithParameter(stmt string, _ map[string]any) (*nebula.ResultSet, error) {
+	e.stmts = append(e.stmts, stmt)
+	if e.res == nil {
+		return nebula.GenResultSet(&graph.ExecutionResponse{})
+	}
+	return e.res, nil
+}
+
+func (e *fakeExecutor) Close() {
+	e.closed = true
+}
+
+// funcExecutor answers the statements with the function
+type funcExecutor func(stmt string) (*nebula.ResultSet, error)
+
+func (f funcExecutor) Execute(stmt string) (*nebula.ResultSet, error) {
+	return f(stmt)
+}
+
+func (f funcExecutor) ExecuteWithParameter(stmt string, _ map[string]any) (*nebula.ResultSet, error) {
+	return f(stmt)
+}
+
+func (f funcExecutor) Close() {}
+
+// newResult builds the result with the columns and the rows, the values should be string, int, bool, time.Time
+// which is converted to datetime, or nil which is converted to empty
+func newResult(t *testing.T, cols []string, rows ...[]any) *nebula.ResultSet {
+	ds := &nebulaType.DataSet{}
+	for _, col := range cols {
+		ds.ColumnNames = append(ds.ColumnNames, []byte(col))
+	}
+	for _, values := range rows {
+		row := &nebulaType.Row{}
+		for _, v := range values {
+			switch v := v.(type) {
+			case string:
+				row.Values = append(row.Values, &nebulaType.Value{SVal: []byte(v)})
+			case int:
+				i := int64(v)
+				row.Values = append(row.Values, &nebulaType.Value{IVal: &i})
+			case bool:
+				row.Values = append(row.Values, &nebulaType.Value{BVal: &v})
+			case time.Time:
+				v = v.UTC()
+				row.Values = append(row.Values, &nebulaType.Value{DtVal: &nebulaType.DateTime{
+					Year: int16(v.Year()), Month: int8(v.Month()), Day: int8(v.Day()),
+					Hour: int8(v.Hour()), Minute: int8(v.Minute()), Sec: int8(v.Second()), Microsec: int32(v.Nanosecond() / 1000),
+				}})
+			case nil:
+				row.Values = append(row.Values, &nebulaType.Value{})
+			default:
+				t.Fatalf("unsupported value %v", v)
+			}
+		}
+		ds.Rows = append(ds.Rows, row)
+	}
+	res, err := nebula.GenResultSet(&graph.ExecutionResponse{Data: ds})
+	assert.NoError(t, err)
+	return res
+}
+
+func TestOpenWithExecutor(t *testing.T) {
+	_, err := OpenWithExecutor(&Config{}, nil)
+	assert.True(t, errors.Is(err, ErrInvalidValue))
+
+	executor := &fakeExecutor{res: newPlayersResult(t, "tim", "tony")}
+	db, err := OpenWithExecutor(&Config{SpaceName: "test"}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+
+	players := make([]*rowsPlayer, 0)
+	assert.NoError(t, db.Lookup("player").Where("player.name == 'tim'").Yield("id(vertex) AS vid, player.name AS name").Find(&players))
+	assert.Len(t, players, 2)
+	assert.Equal(t, "tony", players[1].Name)
+	assert.Len(t, executor.stmts, 1)
+
+	assert.NoError(t, db.Space("other").Raw("YIELD 1").Exec())
+	assert.Equal(t, "USE `other`; YIELD 1", executor.stmts[1])
+	assert.Equal(t, Stats{}, db.Stats())
+
+	assert.NoError(t, db.Close())
+	assert.True(t, executor.closed)
+}
+
+func TestExecutorNoResult(t *testing.T) {
+	executor := funcExecutor(func(string) (*nebula.ResultSet, error) { return nil, nil })
+	db, err := OpenWithExecutor(&Config{}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+
+	var names []string
+	assert.Error(t, db.Raw("YIELD 'tim' AS name").FindCol("name", &names))
+	_, err = db.Raw("YIELD 1").RawResult()
+	assert.Error(t, err)
+	has, err := db.Migrator().HasVertexTag("player")
+	assert.Error(t, err)
+	assert.False(t, has)
+}
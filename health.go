@@ -0,0 +1,99 @@
+package norm
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	pingNGQL = "YIELD 1"
+
+	hostStatusOnline = "ONLINE"
+)
+
+// Ping verifies the connectivity to nebula graph by executing a trivial statement, the primary cluster and each
+// replica are checked. the statement is executed even if the DB or Config is in dry-run mode.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//	defer cancel()
+//	err := db.Ping(ctx)
+func (db *DB) Ping(ctx context.Context) error {
+	if err := db.ping(ctx, db.executor); err != nil {
+		return fmt.Errorf("norm: ping failed: %w", err)
+	}
+	if db.replicas == nil {
+		return nil
+	}
+	for i, executor := range db.replicas.executors {
+		if err := db.ping(ctx, executor); err != nil {
+			return fmt.Errorf("norm: ping replica %d failed: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ping executes the trivial statement by the given executor
+func (db *DB) ping(ctx context.Context, executor Executor) error {
+	tx := db.WithContext(ctx).Raw(pingNGQL)
+	tx.pinned = executor
+	tx.force = true
+	return tx.Exec()
+}
+
+// HostStatus the status of a storage host reported by SHOW HOSTS
+type HostStatus struct {
+	Host                  string `norm:"col:Host"`
+	Port                  int    `norm:"col:Port"`
+	Status                string `norm:"col:Status"`
+	LeaderCount           int    `norm:"col:Leader count"`
+	LeaderDistribution    string `norm:"col:Leader distribution"`
+	PartitionDistribution string `norm:"col:Partition distribution"`
+	Version               string `norm:"col:Version"`
+}
+
+// Online reports whether the host is online
+func (h *HostStatus) Online() bool {
+	return h.Status == hostStatusOnline
+}
+
+// Health the health of the storage hosts of the primary cluster
+type Health struct {
+	Hosts []*HostStatus
+}
+
+// Healthy reports whether there are storage hosts and all of them are online
+func (h *Health) Healthy() bool {
+	return len(h.Hosts) > 0 && len(h.Offline()) == 0
+}
+
+// Offline returns the hosts that are not online
+func (h *Health) Offline() []*HostStatus {
+	offline := make([]*HostStatus, 0)
+	for _, host := range h.Hosts {
+		if !host.Online() {
+			offline = append(offline, host)
+		}
+	}
+	return offline
+}
+
+// HealthCheck reports the status of the storage hosts of the primary cluster by SHOW HOSTS, including whether they
+// are online, the leader counts and the versions. an error is returned only if the statement fails, use
+// Health.Healthy to check the hosts.
+//
+//	health, err := db.HealthCheck()
+//	if err != nil {
+//		return err
+//	}
+//	if !health.Healthy() {
+//		return fmt.Errorf("offline hosts: %v", health.Offline())
+//	}
+func (db *DB) HealthCheck() (*Health, error) {
+	tx := db.UsePrimary().Raw("SHOW HOSTS")
+	tx.force = true
+	hosts := make([]*HostStatus, 0)
+	if err := tx.Find(&hosts); err != nil {
+		return nil, err
+	}
+	return &Health{Hosts: hosts}, nil
+}
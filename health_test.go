@@ -0,0 +1,92 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"testing"
+)
+
+func TestPing(t *testing.T) {
+	primary, replica1, replica2 := &fakeExecutor{}, &fakeExecutor{}, &fakeExecutor{}
+	db, err := OpenWithExecutor(&Config{}, primary, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+	assert.NoError(t, db.Ping(context.Background()))
+	assert.Equal(t, []string{"YIELD 1"}, primary.stmts)
+
+	db.replicas = &replicaSet{executors: []Executor{replica1, replica2}}
+	assert.NoError(t, db.DryRun().Ping(context.Background()))
+	assert.Len(t, primary.stmts, 2)
+	assert.Equal(t, []string{"YIELD 1"}, replica1.stmts)
+	assert.Equal(t, []string{"YIELD 1"}, replica2.stmts)
+
+	replica2.res = newFailedResult(t, nebulaType.ErrorCode_E_RPC_FAILURE, "rpc failure")
+	err = db.Ping(context.Background())
+	assert.ErrorContains(t, err, "ping replica 1 failed")
+	var normErr *Error
+	assert.True(t, errors.As(err, &normErr))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, db.Ping(ctx), context.Canceled)
+}
+
+func TestPingConfigDryRun(t *testing.T) {
+	primary, replica := &fakeExecutor{}, &fakeExecutor{}
+	db, err := OpenWithExecutor(&Config{DryRun: true}, primary, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+	db.replicas = &replicaSet{executors: []Executor{replica}}
+
+	assert.NoError(t, db.Ping(context.Background()))
+	assert.Equal(t, []string{"YIELD 1"}, primary.stmts)
+	assert.Equal(t, []string{"YIELD 1"}, replica.stmts)
+
+	// the other statements are still not executed
+	assert.NoError(t, db.Raw("YIELD 1").Exec())
+	assert.Len(t, primary.stmts, 1)
+}
+
+func TestHealthCheck(t *testing.T) {
+	cols := []string{"Host", "Port", "Status", "Leader count", "Leader distribution", "Partition distribution", "Version"}
+	hosts := [][]any{
+		{"storaged0", 9779, "ONLINE", 5, "basketballplayer:5", "basketballplayer:10", "3.8.0"},
+		{"storaged1", 9779, "OFFLINE", 0, "No valid partition", "basketballplayer:10", "3.8.0"},
+	}
+	res := newResult(t, cols, hosts...)
+
+	primary, replica := &fakeExecutor{res: res}, &fakeExecutor{}
+	db, err := OpenWithExecutor(&Config{}, primary, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+	db.replicas = &replicaSet{executors: []Executor{replica}}
+
+	health, err := db.HealthCheck()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SHOW HOSTS"}, primary.stmts)
+	assert.Empty(t, replica.stmts)
+	assert.Len(t, health.Hosts, 2)
+	assert.Equal(t, &HostStatus{Host: "storaged0", Port: 9779, Status: "ONLINE", LeaderCount: 5, LeaderDistribution: "basketballplayer:5", PartitionDistribution: "basketballplayer:10", Version: "3.8.0"}, health.Hosts[0])
+	assert.False(t, health.Healthy())
+	assert.Equal(t, []*HostStatus{health.Hosts[1]}, health.Offline())
+
+	health.Hosts = health.Hosts[:1]
+	assert.True(t, health.Healthy())
+	assert.False(t, (&Health{}).Healthy())
+
+	primary.res = newFailedResult(t, nebulaType.ErrorCode_E_RPC_FAILURE, "rpc failure")
+	_, err = db.HealthCheck()
+	assert.Error(t, err)
+}
+
+func TestHealthCheckConfigDryRun(t *testing.T) {
+	cols := []string{"Host", "Port", "Status", "Leader count", "Leader distribution", "Partition distribution", "Version"}
+	primary := &fakeExecutor{res: newResult(t, cols, []any{"storaged0", 9779, "ONLINE", 5, "basketballplayer:5", "basketballplayer:10", "3.8.0"})}
+	db, err := OpenWithExecutor(&Config{DryRun: true}, primary, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+
+	health, err := db.HealthCheck()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"SHOW HOSTS"}, primary.stmts)
+	assert.True(t, health.Healthy())
+}
@@ -0,0 +1,150 @@
+package norm
+
+import (
+	"context"
+	"reflect"
+)
+
+// The following interfaces can be implemented by vertex and edge structs to run code around the statements that
+// operate on them, eg: validation or generating vertex ids. A Before* hook that returns an error aborts the statement
+// before it is sent to the server.
+//
+// Hooks are called on the values passed to the statement, for a slice or an array they are called on each element.
+// Pass pointers if the hook has a pointer receiver or needs to modify the value.
+//
+//	func (p *Player) BeforeInsert(ctx context.Context) error {
+//		if p.VID == "" {
+//			p.VID = uuid.NewString()
+//		}
+//		return nil
+//	}
+
+// BeforeInsertInterface is called before the vertexes or edges passed to InsertVertex and InsertEdge are inserted
+type BeforeInsertInterface interface {
+	BeforeInsert(ctx context.Context) error
+}
+
+// AfterInsertInterface is called after the vertexes or edges passed to InsertVertex and InsertEdge are inserted
+type AfterInsertInterface interface {
+	AfterInsert(ctx context.Context) error
+}
+
+// BeforeUpdateInterface is called before the props passed to UpdateVertex, UpsertVertex, UpdateEdge and UpsertEdge
+// are updated
+type BeforeUpdateInterface interface {
+	BeforeUpdate(ctx context.Context) error
+}
+
+// AfterFindInterface is called after the results are assigned to the dest of Find, FindCol, Take and TakeCol
+type AfterFindInterface interface {
+	AfterFind(ctx context.Context) error
+}
+
+// BeforeDeleteInterface is called before the vertexes or edges passed to DeleteVertex and DeleteEdge are deleted
+type BeforeDeleteInterface interface {
+	BeforeDelete(ctx context.Context) error
+}
+
+type hookEvent int
+
+const (
+	hookEventInsert hookEvent = iota + 1
+	hookEventUpdate
+	hookEventDelete
+)
+
+// hookModel is a value passed to the statement on which hooks are called
+type hookModel struct {
+	event hookEvent
+	value any
+}
+
+// addHookModel records the value passed to the statement, hooks are called on it when the statement is executed
+func (db *DB) addHookModel(event hookEvent, value any) {
+	db.hookModels = append(db.hookModels, hookModel{event: event, value: value})
+}
+
+// callBeforeHooks calls the before hooks of the values passed to the statement
+func (db *DB) callBeforeHooks(ctx context.Context) error {
+	for _, model := range db.hookModels {
+		err := walkHookModel(reflect.ValueOf(model.value), func(value any) error {
+			switch model.event {
+			case hookEventInsert:
+				if hook, ok := value.(BeforeInsertInterface); ok {
+					return hook.BeforeInsert(ctx)
+				}
+			case hookEventUpdate:
+				if hook, ok := value.(BeforeUpdateInterface); ok {
+					return hook.BeforeUpdate(ctx)
+				}
+			case hookEventDelete:
+				if hook, ok := value.(BeforeDeleteInterface); ok {
+					return hook.BeforeDelete(ctx)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// callAfterHooks calls the after hooks of the values passed to the statement
+func (db *DB) callAfterHooks(ctx context.Context) error {
+	for _, model := range db.hookModels {
+		if model.event != hookEventInsert {
+			continue
+		}
+		err := walkHookModel(reflect.ValueOf(model.value), func(value any) error {
+			if hook, ok := value.(AfterInsertInterface); ok {
+				return hook.AfterInsert(ctx)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// callAfterFind calls the AfterFind hook of the dest which the results were assigned to
+func callAfterFind(ctx context.Context, dest any) error {
+	return walkHookModel(reflect.ValueOf(dest), func(value any) error {
+		if hook, ok := value.(AfterFindInterface); ok {
+			return hook.AfterFind(ctx)
+		}
+		return nil
+	})
+}
+
+// walkHookModel calls fn on the struct value, or on each element if the value is a slice or an array.
+// addressable structs are passed as pointers so that hooks with pointer receivers are also called.
+func walkHookModel(value reflect.Value, fn func(value any) error) error {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		elem := value.Elem()
+		if elem.Kind() == reflect.Struct && value.Kind() == reflect.Ptr {
+			return fn(value.Interface())
+		}
+		return walkHookModel(elem, fn)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := walkHookModel(value.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if value.CanAddr() {
+			return fn(value.Addr().Interface())
+		}
+		return fn(value.Interface())
+	default:
+	}
+	return nil
+}
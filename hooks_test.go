@@ -0,0 +1,137 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type hookPlayer struct {
+	VID      string `norm:"vertex_id"`
+	Name     string `norm:"prop:name"`
+	found    bool
+	inserted bool
+}
+
+func (p *hookPlayer) VertexID() string {
+	return p.VID
+}
+
+func (p *hookPlayer) VertexTagName() string {
+	return "player"
+}
+
+func (p *hookPlayer) BeforeInsert(_ context.Context) error {
+	if p.Name == "" {
+		return errors.New("name is required")
+	}
+	if p.VID == "" {
+		p.VID = "player_" + p.Name
+	}
+	return nil
+}
+
+func (p *hookPlayer) AfterInsert(_ context.Context) error {
+	p.inserted = true
+	return nil
+}
+
+func (p *hookPlayer) AfterFind(_ context.Context) error {
+	p.found = true
+	return nil
+}
+
+type hookFollow struct {
+	SrcID string `norm:"edge_src_id"`
+	DstID string `norm:"edge_dst_id"`
+}
+
+func (f *hookFollow) EdgeTypeName() string {
+	return "follow"
+}
+
+func (f *hookFollow) BeforeDelete(_ context.Context) error {
+	if f.SrcID == f.DstID {
+		return errors.New("self follow is not deletable")
+	}
+	return nil
+}
+
+type hookPlayerUpdate struct {
+	Name string `norm:"prop:name"`
+}
+
+func (p hookPlayerUpdate) BeforeUpdate(_ context.Context) error {
+	return errors.New("update is not allowed")
+}
+
+func TestCallBeforeHooks(t *testing.T) {
+	ctx := context.Background()
+
+	db := &DB{}
+	player := &hookPlayer{Name: "kobe"}
+	db.addHookModel(hookEventInsert, player)
+	assert.NoError(t, db.callBeforeHooks(ctx))
+	assert.Equal(t, "player_kobe", player.VID)
+
+	db = &DB{}
+	players := []hookPlayer{{Name: "kobe"}, {VID: "player101"}}
+	db.addHookModel(hookEventInsert, players)
+	assert.EqualError(t, db.callBeforeHooks(ctx), "name is required")
+	assert.Equal(t, "player_kobe", players[0].VID)
+
+	db = &DB{}
+	db.addHookModel(hookEventUpdate, hookPlayerUpdate{Name: "kobe"})
+	assert.EqualError(t, db.callBeforeHooks(ctx), "update is not allowed")
+
+	db = &DB{}
+	db.addHookModel(hookEventDelete, "player101")
+	db.addHookModel(hookEventUpdate, map[string]any{"name": "kobe"})
+	assert.NoError(t, db.callBeforeHooks(ctx))
+}
+
+func TestCallAfterFind(t *testing.T) {
+	ctx := context.Background()
+
+	player := new(hookPlayer)
+	assert.NoError(t, callAfterFind(ctx, player))
+	assert.True(t, player.found)
+
+	players := make([]*hookPlayer, 0)
+	players = append(players, &hookPlayer{}, nil, &hookPlayer{})
+	assert.NoError(t, callAfterFind(ctx, &players))
+	assert.True(t, players[0].found)
+	assert.True(t, players[2].found)
+
+	playerValues := []hookPlayer{{}, {}}
+	assert.NoError(t, callAfterFind(ctx, &playerValues))
+	assert.True(t, playerValues[0].found)
+	assert.True(t, playerValues[1].found)
+}
+
+func TestHooksExecute(t *testing.T) {
+	executor := &fakeExecutor{}
+	db, err := OpenWithExecutor(&Config{}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+
+	// the statement does not reach the executor if a before hook fails
+	player := &hookPlayer{}
+	assert.EqualError(t, db.InsertVertex(player).Exec(), "name is required")
+	assert.False(t, player.inserted)
+	assert.Empty(t, executor.stmts)
+
+	player = &hookPlayer{Name: "kobe"}
+	assert.NoError(t, db.InsertVertex(player).Exec())
+	assert.True(t, player.inserted)
+	assert.Equal(t, []string{`INSERT VERTEX player(name) VALUES "player_kobe":("kobe");`}, executor.stmts)
+
+	follow := &hookFollow{SrcID: "player100", DstID: "player100"}
+	assert.EqualError(t, db.DeleteEdge("follow", follow).Exec(), "self follow is not deletable")
+	assert.Len(t, executor.stmts, 1)
+
+	follow.DstID = "player101"
+	assert.NoError(t, db.DeleteEdge("follow", follow).Exec())
+	assert.Len(t, executor.stmts, 2)
+}
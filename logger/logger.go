@@ -6,6 +6,8 @@ import (
 	"io"
 	"log"
 	"os"
+	"strings"
+	"time"
 )
 
 type Level int
@@ -19,8 +21,15 @@ const (
 )
 
 type TraceRecord struct {
-	NGQL string
-	Err  error
+	NGQL    string
+	Params  map[string]any // query parameters, only present when the statement is parameterized
+	Err     error
+	Begin   time.Time     // the time when the execution began
+	Elapsed time.Duration // the time taken by the execution, including the network round trip
+	Rows    int           // number of records returned
+	Latency time.Duration // the time taken by the server to execute the statement
+	Space   string        // the graph space in which the statement is executed
+	Kind    string        // the kind of the statement, eg: GO, FETCH, INSERT VERTEX
 }
 
 type Interface interface {
@@ -46,6 +55,9 @@ type defaultLogger struct {
 type Config struct {
 	Colorful bool
 	LogLevel Level
+
+	// SlowThreshold statements taking longer than it are logged as slow statements at warn level, 0 means disabled
+	SlowThreshold time.Duration
 }
 
 var Default = New(os.Stdout, Config{
@@ -104,9 +116,27 @@ func (l *defaultLogger) Trace(ctx context.Context, record *TraceRecord) {
 	if l.LogLevel >= SilentLevel || record == nil {
 		return
 	}
-	if record.Err == nil {
-		l.message(ctx, DebugLevel, l.debugStr+"[norm] nGQL: %s", record.NGQL)
-	} else {
-		l.message(ctx, DebugLevel, l.debugStr+"[norm] nGQL: %s err: %v", record.NGQL, record.Err)
+	if l.SlowThreshold > 0 && record.Elapsed > l.SlowThreshold {
+		l.message(ctx, WarnLevel, l.warnStr+"[norm] SLOW nGQL >= %s %s", l.SlowThreshold, formatRecord(record))
+		return
+	}
+	l.message(ctx, DebugLevel, l.debugStr+"[norm] %s", formatRecord(record))
+}
+
+// formatRecord formats the trace record, eg: [1.234ms] [rows:1] nGQL: YIELD 1;
+func formatRecord(record *TraceRecord) string {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("[%.3fms] [rows:%d] ", float64(record.Elapsed.Nanoseconds())/1e6, record.Rows))
+	if record.Space != "" {
+		b.WriteString(fmt.Sprintf("[space:%s] ", record.Space))
+	}
+	b.WriteString("nGQL: ")
+	b.WriteString(record.NGQL)
+	if len(record.Params) > 0 {
+		b.WriteString(fmt.Sprintf(" params: %v", record.Params))
+	}
+	if record.Err != nil {
+		b.WriteString(fmt.Sprintf(" err: %v", record.Err))
 	}
+	return b.String()
 }
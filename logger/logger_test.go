@@ -1,10 +1,13 @@
 package logger
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"github.com/stretchr/testify/assert"
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLogger(t *testing.T) {
@@ -22,6 +25,10 @@ func TestLogger(t *testing.T) {
 		NGQL: `GO FROM "player102" OVER serve YIELD dst(edge);`,
 		Err:  errors.New("error"),
 	})
+	logger.Trace(ctx, &TraceRecord{
+		NGQL:   `LOOKUP ON player WHERE player.name == $p1 YIELD id(vertex);`,
+		Params: map[string]any{"p1": "Tim Duncan"},
+	})
 
 	logger = logger.LogMode(WarnLevel)
 	logger.Debug(ctx, "debug message")
@@ -34,3 +41,27 @@ func TestLogger(t *testing.T) {
 	logger = logger.LogMode(SilentLevel)
 	logger.Error(ctx, "error message")
 }
+
+func TestTraceSlow(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := New(buf, Config{
+		LogLevel:      WarnLevel,
+		SlowThreshold: 100 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	logger.Trace(ctx, &TraceRecord{
+		NGQL:    `GO FROM "player102" OVER serve YIELD dst(edge);`,
+		Elapsed: 10 * time.Millisecond,
+		Rows:    2,
+	})
+	assert.Empty(t, buf.String())
+
+	logger.Trace(ctx, &TraceRecord{
+		NGQL:    `GO 1 TO 5 STEPS FROM "player102" OVER * YIELD dst(edge);`,
+		Elapsed: 150 * time.Millisecond,
+		Rows:    10,
+		Space:   "basketballplayer",
+	})
+	assert.Contains(t, buf.String(), `[WARN] [norm] SLOW nGQL >= 100ms [150.000ms] [rows:10] [space:basketballplayer] nGQL: GO 1 TO 5 STEPS FROM "player102" OVER * YIELD dst(edge);`)
+}
@@ -0,0 +1,311 @@
+package norm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/haysons/norm/clause"
+	"github.com/haysons/norm/statement"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Migration a versioned migration of the graph space, Up applies it and Down reverts it. Down may be nil if the
+// migration can not be rolled back.
+//
+// Note: the schema changes of nebula graph are not transactional. if Up fails halfway, the migration is not
+// recorded as applied and is applied again next time, so Up should be safe to re-run, eg: with IF NOT EXISTS.
+type Migration struct {
+	ID   string
+	Up   func(m *Migrator) error
+	Down func(m *Migrator) error
+}
+
+// AppliedMigration the migration recorded as applied in the graph space
+type AppliedMigration struct {
+	ID        string    `json:"id"`
+	AppliedAt time.Time `json:"applied_at"`
+}
+
+// migrationTagName the tag of the vertex recording the applied migrations, which also holds the lock preventing
+// the migrations from being applied by several Migrators at once
+const migrationTagName = "norm_migration"
+
+type migrationHistory struct {
+	VID      string `norm:"vertex_id"`
+	Applied  string `norm:"prop:applied;not_null;default:''"`   // the applied migrations encoded in json
+	LockedBy string `norm:"prop:locked_by;not_null;default:''"` // the owner of the lock, empty if not locked
+	LockedAt int64  `norm:"prop:locked_at;not_null;default:0"`  // the timestamp when the lock was acquired
+}
+
+func (h migrationHistory) VertexID() string {
+	return h.VID
+}
+
+func (h migrationHistory) VertexTagName() string {
+	return migrationTagName
+}
+
+// WithMigrations registers the versioned migrations in the order they should be applied, which can be applied by
+// Migrate, MigrateTo and rolled back by RollbackLast.
+//
+//	m := db.Migrator(norm.WithMigrations(
+//		&norm.Migration{
+//			ID: "20250301_create_player",
+//			Up: func(m *norm.Migrator) error { return m.CreateVertexTags(Player{}, true) },
+//			Down: func(m *norm.Migrator) error { return m.DropVertexTag("player", true) },
+//		},
+//	))
+//	err := m.Migrate()
+func WithMigrations(migrations ...*Migration) MigratorOption {
+	return func(m *Migrator) {
+		m.migrations = append(m.migrations, migrations...)
+	}
+}
+
+// Migrate applies all the registered migrations which have not been applied
+func (m *Migrator) Migrate() error {
+	return m.migrateTo(func(int) (int, error) {
+		return len(m.migrations), nil
+	})
+}
+
+// MigrateTo applies or rolls back the registered migrations, until the migration with the id is the last one
+// applied. all the applied migrations are rolled back if the id is empty.
+func (m *Migrator) MigrateTo(id string) error {
+	return m.migrateTo(func(int) (int, error) {
+		if id == "" {
+			return 0, nil
+		}
+		for i, migration := range m.migrations {
+			if migration.ID == id {
+				return i + 1, nil
+			}
+		}
+		return 0, fmt.Errorf("norm: %w, migration %s is not registered", ErrInvalidValue, id)
+	})
+}
+
+// RollbackLast rolls back the last applied migration
+func (m *Migrator) RollbackLast() error {
+	return m.migrateTo(func(applied int) (int, error) {
+		if applied == 0 {
+			return 0, errors.New("norm: no migration applied")
+		}
+		return applied - 1, nil
+	})
+}
+
+// migrateTo applies or rolls back the migrations with the lock held, until the number of the applied ones
+// reaches the target
+func (m *Migrator) migrateTo(target func(applied int) (int, error)) (err error) {
+	if err = m.checkMigrations(); err != nil {
+		return err
+	}
+	vid, err := m.migrationVID()
+	if err != nil {
+		return err
+	}
+	if err = m.ensureMigrationTag(); err != nil {
+		return err
+	}
+	owner, err := m.lockMigrations(vid)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if errUnlock := m.unlockMigrations(vid, owner); err == nil {
+			err = errUnlock
+		}
+	}()
+
+	applied, err := m.appliedMigrations(vid)
+	if err != nil {
+		return err
+	}
+	for i, migration := range applied {
+		if i >= len(m.migrations) || m.migrations[i].ID != migration.ID {
+			return fmt.Errorf("norm: applied migration %s is not registered at position %d", migration.ID, i)
+		}
+	}
+	n, err := target(len(applied))
+	if err != nil {
+		return err
+	}
+	for len(applied) < n {
+		migration := m.migrations[len(applied)]
+		if err = migration.Up(m); err != nil {
+			return fmt.Errorf("norm: apply migration %s failed: %w", migration.ID, err)
+		}
+		applied = append(applied, &AppliedMigration{ID: migration.ID, AppliedAt: time.Now()})
+		if err = m.recordMigrations(vid, owner, applied); err != nil {
+			return err
+		}
+	}
+	for len(applied) > n {
+		migration := m.migrations[len(applied)-1]
+		if migration.Down == nil {
+			return fmt.Errorf("norm: migration %s can not be rolled back", migration.ID)
+		}
+		if err = migration.Down(m); err != nil {
+			return fmt.Errorf("norm: roll back migration %s failed: %w", migration.ID, err)
+		}
+		applied = applied[:len(applied)-1]
+		if err = m.recordMigrations(vid, owner, applied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkMigrations checks that the registered migrations have unique ids and can be applied
+func (m *Migrator) checkMigrations() error {
+	ids := make(map[string]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		if migration == nil || migration.ID == "" || migration.Up == nil {
+			return fmt.Errorf("norm: %w, migration should have id and up", ErrInvalidValue)
+		}
+		if ids[migration.ID] {
+			return fmt.Errorf("norm: %w, duplicate migration %s", ErrInvalidValue, migration.ID)
+		}
+		ids[migration.ID] = true
+	}
+	return nil
+}
+
+// AppliedMigrations returns the migrations recorded as applied in the graph space, in the order they were applied
+func (m *Migrator) AppliedMigrations() ([]*AppliedMigration, error) {
+	hasTag, err := m.HasVertexTag(migrationTagName)
+	if err != nil || !hasTag {
+		return make([]*AppliedMigration, 0), err
+	}
+	vid, err := m.migrationVID()
+	if err != nil {
+		return nil, err
+	}
+	return m.appliedMigrations(vid)
+}
+
+// ForceUnlock releases the lock of the migrations, which is left held if the process applying them crashed
+func (m *Migrator) ForceUnlock() error {
+	vid, err := m.migrationVID()
+	if err != nil {
+		return err
+	}
+	return m.migrationRaw(`UPSERT VERTEX ON `+migrationTagName+` ? SET locked_by = ""`, vid).
+		Exec()
+}
+
+// migrationVID returns the vid of the vertex recording the applied migrations, which depends on the vid type of
+// the graph space
+func (m *Migrator) migrationVID() (any, error) {
+	if m.db.isDryRun() {
+		return migrationTagName, nil
+	}
+	desc, err := m.DescSpace(m.db.spaceName())
+	if err != nil {
+		return nil, err
+	}
+	if desc.VIDType == "INT64" {
+		return clause.Expr{Str: `hash("` + migrationTagName + `")`}, nil
+	}
+	return migrationTagName, nil
+}
+
+// ensureMigrationTag creates the tag recording the applied migrations if it does not exist
+func (m *Migrator) ensureMigrationTag() error {
+	hasTag, err := m.HasVertexTag(migrationTagName)
+	if err != nil || hasTag {
+		return err
+	}
+	if err = m.CreateVertexTags(migrationHistory{}, true); err != nil {
+		return err
+	}
+	if m.db.isDryRun() {
+		return nil
+	}
+	// the new tag can be used after two heartbeat cycles
+	timer := time.NewTimer(2 * m.heartbeatInterval)
+	defer timer.Stop()
+	ctx := m.db.Context()
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("norm: wait for tag %s to be ready failed: %w", migrationTagName, ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
+
+// lockMigrations acquires the lock of the migrations and returns the owner of the lock
+func (m *Migrator) lockMigrations(vid any) (string, error) {
+	hostname, _ := os.Hostname()
+	owner := hostname + ":" + strconv.Itoa(os.Getpid()) + ":" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	owners := make([]string, 0, 1)
+	err := m.migrationRaw(`UPSERT VERTEX ON `+migrationTagName+` ? SET locked_by = ?, locked_at = timestamp() WHEN locked_by == "" YIELD locked_by AS locked_by`, vid, owner).
+		FindCol("locked_by", &owners)
+	if err != nil {
+		return "", err
+	}
+	if m.db.isDryRun() {
+		return owner, nil
+	}
+	if len(owners) == 0 || owners[0] != owner {
+		lockedBy := "unknown"
+		if len(owners) > 0 {
+			lockedBy = owners[0]
+		}
+		return "", fmt.Errorf("norm: %w by %s", ErrMigrationLocked, lockedBy)
+	}
+	return owner, nil
+}
+
+// unlockMigrations releases the lock of the migrations if it is still held by the owner
+func (m *Migrator) unlockMigrations(vid any, owner string) error {
+	return m.migrationRaw(`UPDATE VERTEX ON `+migrationTagName+` ? SET locked_by = "" WHEN locked_by == ?`, vid, owner).
+		Exec()
+}
+
+// appliedMigrations reads the applied migrations from the vertex
+func (m *Migrator) appliedMigrations(vid any) ([]*AppliedMigration, error) {
+	values := make([]string, 0, 1)
+	err := m.migrationRaw(`FETCH PROP ON `+migrationTagName+` ? YIELD properties(vertex).applied AS applied`, vid).
+		FindCol("applied", &values)
+	if err != nil {
+		return nil, err
+	}
+	applied := make([]*AppliedMigration, 0)
+	if len(values) == 0 || values[0] == "" {
+		return applied, nil
+	}
+	if err = json.Unmarshal([]byte(values[0]), &applied); err != nil {
+		return nil, fmt.Errorf("norm: decode applied migrations failed: %w", err)
+	}
+	return applied, nil
+}
+
+// recordMigrations records the applied migrations in the vertex, the lock should be held by the owner
+func (m *Migrator) recordMigrations(vid any, owner string, applied []*AppliedMigration) error {
+	data, err := json.Marshal(applied)
+	if err != nil {
+		return err
+	}
+	owners := make([]string, 0, 1)
+	err = m.migrationRaw(`UPDATE VERTEX ON `+migrationTagName+` ? SET applied = ? WHEN locked_by == ? YIELD locked_by AS locked_by`, vid, string(data), owner).
+		FindCol("locked_by", &owners)
+	if err != nil {
+		return err
+	}
+	if !m.db.isDryRun() && (len(owners) == 0 || owners[0] != owner) {
+		return fmt.Errorf("norm: record applied migrations failed: %w, the lock is lost", ErrMigrationLocked)
+	}
+	return nil
+}
+
+// migrationRaw builds the statement about the migrations with the arguments formatted inline, so that the statement
+// does not depend on Config.ParameterizedQuery
+func (m *Migrator) migrationRaw(raw string, args ...any) *DB {
+	tx := m.primary(m.db.Context())
+	tx.Statement = statement.New()
+	return tx.Raw(raw, args...)
+}
@@ -0,0 +1,216 @@
+package norm
+
+import (
+	"errors"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var (
+	upsertLockRe = regexp.MustCompile(`^UPSERT VERTEX ON norm_migration "norm_migration" SET locked_by = (".*"), locked_at = timestamp\(\) WHEN locked_by == "" YIELD locked_by AS locked_by$`)
+	unlockRe     = regexp.MustCompile(`^UPDATE VERTEX ON norm_migration "norm_migration" SET locked_by = "" WHEN locked_by == (".*")$`)
+	recordRe     = regexp.MustCompile(`^UPDATE VERTEX ON norm_migration "norm_migration" SET applied = (".*") WHEN locked_by == (".*") YIELD locked_by AS locked_by$`)
+)
+
+// migrationCluster simulates the statements of the versioned migrations
+type migrationCluster struct {
+	t        *testing.T
+	hasTag   bool
+	applied  string
+	lockedBy string
+	stmts    []string // the statements executed except the ones about the history and the lock
+	conf     Config
+}
+
+func (c *migrationCluster) execute(stmt string) (*nebula.ResultSet, error) {
+	unquote := func(s string) string {
+		s, err := strconv.Unquote(s)
+		assert.NoError(c.t, err)
+		return s
+	}
+	lockedBy := func() (*nebula.ResultSet, error) {
+		return newResult(c.t, []string{"locked_by"}, []any{c.lockedBy}), nil
+	}
+	switch {
+	case stmt == "DESCRIBE SPACE test":
+		cols := []string{"ID", "Name", "Partition Number", "Replica Factor", "Charset", "Collate", "Vid Type", "Zones", "Comment"}
+		return newResult(c.t, cols, []any{1, "test", 10, 1, "utf8", "utf8_bin", "FIXED_STRING(32)", "default_zone", ""}), nil
+	case stmt == "SHOW TAGS":
+		if c.hasTag {
+			return newResult(c.t, []string{"Name"}, []any{migrationTagName}), nil
+		}
+		return newResult(c.t, []string{"Name"}), nil
+	case strings.HasPrefix(stmt, "CREATE TAG IF NOT EXISTS norm_migration("):
+		c.hasTag = true
+		return newResult(c.t, nil), nil
+	case upsertLockRe.MatchString(stmt):
+		if c.lockedBy == "" {
+			c.lockedBy = unquote(upsertLockRe.FindStringSubmatch(stmt)[1])
+		}
+		return lockedBy()
+	case unlockRe.MatchString(stmt):
+		if c.lockedBy == unquote(unlockRe.FindStringSubmatch(stmt)[1]) {
+			c.lockedBy = ""
+		}
+		return newResult(c.t, nil), nil
+	case recordRe.MatchString(stmt):
+		matches := recordRe.FindStringSubmatch(stmt)
+		if c.lockedBy == unquote(matches[2]) {
+			c.applied = unquote(matches[1])
+		}
+		return lockedBy()
+	case stmt == `FETCH PROP ON norm_migration "norm_migration" YIELD properties(vertex).applied AS applied`:
+		return newResult(c.t, []string{"applied"}, []any{c.applied}), nil
+	case stmt == `UPSERT VERTEX ON norm_migration "norm_migration" SET locked_by = ""`:
+		c.lockedBy = ""
+		return newResult(c.t, nil), nil
+	}
+	c.stmts = append(c.stmts, stmt)
+	return newResult(c.t, nil), nil
+}
+
+func (c *migrationCluster) migrator(migrations ...*Migration) *Migrator {
+	conf := c.conf
+	conf.SpaceName = "test"
+	db, err := OpenWithExecutor(&conf, funcExecutor(c.execute), WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(c.t, err)
+	return db.Migrator(WithHeartbeatInterval(0), WithMigrations(migrations...))
+}
+
+func (c *migrationCluster) appliedIDs(m *Migrator) []string {
+	applied, err := m.AppliedMigrations()
+	assert.NoError(c.t, err)
+	ids := make([]string, 0, len(applied))
+	for _, migration := range applied {
+		assert.False(c.t, migration.AppliedAt.IsZero())
+		ids = append(ids, migration.ID)
+	}
+	return ids
+}
+
+func newTestMigration(id string) *Migration {
+	return &Migration{
+		ID: id,
+		Up: func(m *Migrator) error {
+			return m.db.Raw("UP " + id).Exec()
+		},
+		Down: func(m *Migrator) error {
+			return m.db.Raw("DOWN " + id).Exec()
+		},
+	}
+}
+
+func TestMigratorMigrate(t *testing.T) {
+	c := &migrationCluster{t: t}
+	m := c.migrator(newTestMigration("1"), newTestMigration("2"), newTestMigration("3"))
+	assert.Empty(t, c.appliedIDs(m))
+
+	assert.NoError(t, m.Migrate())
+	assert.True(t, c.hasTag)
+	assert.Equal(t, []string{"UP 1", "UP 2", "UP 3"}, c.stmts)
+	assert.Equal(t, []string{"1", "2", "3"}, c.appliedIDs(m))
+	assert.Empty(t, c.lockedBy)
+
+	// nothing to apply
+	c.stmts = nil
+	assert.NoError(t, m.Migrate())
+	assert.Empty(t, c.stmts)
+
+	assert.NoError(t, m.RollbackLast())
+	assert.Equal(t, []string{"1", "2"}, c.appliedIDs(m))
+	assert.NoError(t, m.MigrateTo("1"))
+	assert.Equal(t, []string{"1"}, c.appliedIDs(m))
+	assert.NoError(t, m.MigrateTo("3"))
+	assert.Equal(t, []string{"1", "2", "3"}, c.appliedIDs(m))
+	assert.NoError(t, m.MigrateTo(""))
+	assert.Empty(t, c.appliedIDs(m))
+	assert.Equal(t, []string{"DOWN 3", "DOWN 2", "UP 2", "UP 3", "DOWN 3", "DOWN 2", "DOWN 1"}, c.stmts)
+
+	assert.Error(t, m.RollbackLast())
+	assert.True(t, errors.Is(m.MigrateTo("4"), ErrInvalidValue))
+	assert.Empty(t, c.lockedBy)
+}
+
+func TestMigratorMigrateFailed(t *testing.T) {
+	c := &migrationCluster{t: t}
+	failed := &Migration{ID: "2", Up: func(m *Migrator) error {
+		return errors.New("up failed")
+	}}
+	m := c.migrator(newTestMigration("1"), failed)
+	assert.Error(t, m.Migrate())
+	assert.Equal(t, []string{"1"}, c.appliedIDs(m))
+	assert.Empty(t, c.lockedBy)
+
+	// the migration without down can not be rolled back
+	failed.Up = func(m *Migrator) error { return nil }
+	assert.NoError(t, m.Migrate())
+	assert.Error(t, m.RollbackLast())
+	assert.Equal(t, []string{"1", "2"}, c.appliedIDs(m))
+
+	// the applied migrations do not match the registered ones
+	m = c.migrator(newTestMigration("2"))
+	assert.Error(t, m.Migrate())
+
+	m = c.migrator(newTestMigration("1"), newTestMigration("1"))
+	assert.True(t, errors.Is(m.Migrate(), ErrInvalidValue))
+	m = c.migrator(&Migration{ID: "1"})
+	assert.True(t, errors.Is(m.Migrate(), ErrInvalidValue))
+}
+
+func TestMigratorMigrateLocked(t *testing.T) {
+	c := &migrationCluster{t: t, lockedBy: "deployer-1"}
+	m := c.migrator(newTestMigration("1"))
+	err := m.Migrate()
+	assert.True(t, errors.Is(err, ErrMigrationLocked))
+	assert.Contains(t, err.Error(), "deployer-1")
+	assert.Equal(t, "deployer-1", c.lockedBy)
+	assert.Empty(t, c.appliedIDs(m))
+
+	// the lock is taken over while migrating
+	c.lockedBy = ""
+	m = c.migrator(&Migration{ID: "1", Up: func(m *Migrator) error {
+		c.lockedBy = "deployer-1"
+		return nil
+	}})
+	assert.True(t, errors.Is(m.Migrate(), ErrMigrationLocked))
+	assert.Empty(t, c.appliedIDs(m))
+
+	assert.NoError(t, m.ForceUnlock())
+	assert.Empty(t, c.lockedBy)
+	m = c.migrator(newTestMigration("1"))
+	assert.NoError(t, m.Migrate())
+	assert.Equal(t, []string{"1"}, c.appliedIDs(m))
+}
+
+func TestMigratorMigrateDryRun(t *testing.T) {
+	c := &migrationCluster{t: t}
+	m := c.migrator(newTestMigration("1"))
+	m.db = m.db.DryRun()
+	assert.NoError(t, m.Migrate())
+	assert.Empty(t, c.stmts)
+	assert.False(t, c.hasTag)
+
+	c = &migrationCluster{t: t, conf: Config{DryRun: true}}
+	assert.NoError(t, c.migrator(newTestMigration("1")).Migrate())
+	assert.Empty(t, c.stmts)
+	assert.False(t, c.hasTag)
+}
+
+func TestMigratorMigrateParameterized(t *testing.T) {
+	// the statements about the history and the lock are built inline whatever Config.ParameterizedQuery is
+	c := &migrationCluster{t: t, conf: Config{ParameterizedQuery: true}}
+	m := c.migrator(newTestMigration("1"), newTestMigration("2"))
+	assert.NoError(t, m.Migrate())
+	assert.Equal(t, []string{"UP 1", "UP 2"}, c.stmts)
+	assert.Equal(t, []string{"1", "2"}, c.appliedIDs(m))
+	assert.Empty(t, c.lockedBy)
+
+	c.lockedBy = "other"
+	assert.NoError(t, m.ForceUnlock())
+	assert.Empty(t, c.lockedBy)
+}
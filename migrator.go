@@ -5,20 +5,69 @@ import (
 	"github.com/haysons/norm/resolver"
 	"reflect"
 	"strings"
+	"time"
+)
+
+const (
+	defaultPollInterval      = time.Second
+	defaultHeartbeatInterval = 10 * time.Second
 )
 
 type Migrator struct {
-	db *DB
+	db                *DB
+	pollInterval      time.Duration
+	heartbeatInterval time.Duration
+	rebuildIndexes    bool
+	migrations        []*Migration
+}
+
+// MigratorOption configures the Migrator
+type MigratorOption func(m *Migrator)
+
+// WithPollInterval specifies the interval of polling the status of indexes and jobs, default is 1s
+func WithPollInterval(interval time.Duration) MigratorOption {
+	return func(m *Migrator) {
+		m.pollInterval = interval
+	}
+}
+
+// WithHeartbeatInterval specifies the heartbeat interval of nebula graph, which is heartbeat_interval_secs of the
+// services, default is 10s. a new index can be used after two heartbeat cycles.
+func WithHeartbeatInterval(interval time.Duration) MigratorOption {
+	return func(m *Migrator) {
+		m.heartbeatInterval = interval
+	}
+}
+
+// WithRebuildIndexes makes AutoMigrateVertexes and AutoMigrateEdges wait for the indexes they created to be ready,
+// then rebuild them and wait for the rebuild jobs to finish, with the context of the DB.
+func WithRebuildIndexes() MigratorOption {
+	return func(m *Migrator) {
+		m.rebuildIndexes = true
+	}
 }
 
 // Migrator creates a new Migrator instance based on the current DB object
-func (db *DB) Migrator() *Migrator {
-	return &Migrator{db: db}
+func (db *DB) Migrator(opts ...MigratorOption) *Migrator {
+	return NewMigrator(db, opts...)
 }
 
 // NewMigrator creates a new Migrator instance based on the specified DB object
-func NewMigrator(db *DB) *Migrator {
-	return &Migrator{db: db}
+func NewMigrator(db *DB, opts ...MigratorOption) *Migrator {
+	// all the statements are executed on the primary cluster, so that the schemas are read from the same cluster
+	// where they are changed
+	primary := *db.getInstance()
+	primary.clone = 1
+	primary.target = routePrimary
+	m := &Migrator{
+		db:                &primary,
+		pollInterval:      defaultPollInterval,
+		heartbeatInterval: defaultHeartbeatInterval,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 // AutoMigrateVertexes automatically migrates all tags associated with the given vertices
@@ -31,8 +80,8 @@ func NewMigrator(db *DB) *Migrator {
 // indexes do not exist in the current graph space, they will be created.
 //
 // Note: Index creation is asynchronous in NebulaGraph, so newly created indexes cannot
-// be immediately rebuilt within this method. It is recommended to call RebuildVertexTagIndexes
-// manually after migration to ensure indexes are properly built.
+// be immediately rebuilt. Create the Migrator with WithRebuildIndexes to wait for them to be ready
+// and rebuild them within this method, otherwise call RebuildAndWait manually after migration.
 //
 // For safety reasons, existing indexes will not be dropped.
 func (m *Migrator) AutoMigrateVertexes(vertexes ...any) error {
@@ -80,31 +129,16 @@ func (m *Migrator) autoAlterVertexTags(tag *resolver.VertexTag) error {
 	if err != nil {
 		return err
 	}
-	propsExist := make(map[string]*PropDesc)
-	for _, tagProp := range tagProps {
-		propsExist[tagProp.Field] = tagProp
-	}
-	alterOp := clause.AlterOperate{}
-	for _, propNew := range tag.GetProps() {
-		// Add the property if it does not exist
-		propExist, ok := propsExist[propNew.Name]
-		if !ok {
-			alterOp.AddProps = append(alterOp.AddProps, propNew.Name)
-			continue
-		}
-		// Apply change if the property differs from existing definition
-		if m.isPropChanged(propExist, propNew) {
-			alterOp.ChangeProps = append(alterOp.ChangeProps, propNew.Name)
-		}
-	}
-	if len(alterOp.AddProps) == 0 && len(alterOp.ChangeProps) == 0 {
+	change := m.diffProps(tag.TagName, tagProps, tag.GetProps())
+	if !change.altered() {
 		return nil
 	}
-	return m.AlterVertexTag(tag, alterOp)
+	return m.AlterVertexTag(tag, change.alterOperate())
 }
 
 func (m *Migrator) autoCreateTagIndexes(tag *resolver.VertexTag) error {
 	indexes := tag.GetIndexes()
+	created := make([]string, 0, len(indexes))
 	for _, index := range indexes {
 		hasIndex, err := m.HasVertexTagIndex(index.Name)
 		if err != nil {
@@ -116,8 +150,9 @@ func (m *Migrator) autoCreateTagIndexes(tag *resolver.VertexTag) error {
 		if err = m.CreateVertexTagsIndex(index, true); err != nil {
 			return err
 		}
+		created = append(created, index.Name)
 	}
-	return nil
+	return m.autoRebuildIndexes(resolver.IndexTypeTag, created)
 }
 
 // AutoMigrateEdges automatically migrates edge schemas.
@@ -129,8 +164,8 @@ func (m *Migrator) autoCreateTagIndexes(tag *resolver.VertexTag) error {
 // indexes do not exist in the current graph space, they will be created.
 //
 // Note: Index creation is asynchronous in NebulaGraph, so newly created indexes cannot
-// be immediately rebuilt within this method. It is recommended to call RebuildEdgeIndexes
-// manually after migration to ensure indexes are properly built.
+// be immediately rebuilt. Create the Migrator with WithRebuildIndexes to wait for them to be ready
+// and rebuild them within this method, otherwise call RebuildAndWait manually after migration.
 //
 // For safety reasons, existing edges or their properties and indexes will not be dropped.
 func (m *Migrator) AutoMigrateEdges(edges ...any) error {
@@ -168,31 +203,16 @@ func (m *Migrator) autoAlterEdge(edge *resolver.EdgeSchema) error {
 	if err != nil {
 		return err
 	}
-	propsExist := make(map[string]*PropDesc)
-	for _, edgeProp := range edgeProps {
-		propsExist[edgeProp.Field] = edgeProp
-	}
-	alterOp := clause.AlterOperate{}
-	for _, propNew := range edge.GetProps() {
-		// Add the property if it does not exist
-		propExist, ok := propsExist[propNew.Name]
-		if !ok {
-			alterOp.AddProps = append(alterOp.AddProps, propNew.Name)
-			continue
-		}
-		// Apply change if the property differs from existing definition
-		if m.isPropChanged(propExist, propNew) {
-			alterOp.ChangeProps = append(alterOp.ChangeProps, propNew.Name)
-		}
-	}
-	if len(alterOp.AddProps) == 0 && len(alterOp.ChangeProps) == 0 {
+	change := m.diffProps(edge.GetTypeName(), edgeProps, edge.GetProps())
+	if !change.altered() {
 		return nil
 	}
-	return m.AlterEdge(edge, alterOp)
+	return m.AlterEdge(edge, change.alterOperate())
 }
 
 func (m *Migrator) autoCreateEdgeIndexes(edge *resolver.EdgeSchema) error {
 	indexes := edge.GetIndexes()
+	created := make([]string, 0, len(indexes))
 	for _, index := range indexes {
 		hasIndex, err := m.HasEdgeIndex(index.Name)
 		if err != nil {
@@ -204,46 +224,62 @@ func (m *Migrator) autoCreateEdgeIndexes(edge *resolver.EdgeSchema) error {
 		if err = m.CreateEdgeIndex(index, true); err != nil {
 			return err
 		}
+		created = append(created, index.Name)
 	}
-	return nil
+	return m.autoRebuildIndexes(resolver.IndexTypeEdge, created)
+}
+
+// autoRebuildIndexes waits for the created indexes to be ready and rebuilds them if WithRebuildIndexes is specified
+func (m *Migrator) autoRebuildIndexes(indexType resolver.IndexType, indexNames []string) error {
+	if !m.rebuildIndexes || len(indexNames) == 0 {
+		return nil
+	}
+	ctx := m.db.Context()
+	if err := m.WaitIndexReady(ctx, indexNames...); err != nil {
+		return err
+	}
+	return m.RebuildAndWait(ctx, indexType, indexNames...)
 }
 
 // isPropChanged determines whether a property definition has changed
 // by comparing type, nullability, and default value.
 func (m *Migrator) isPropChanged(propExist *PropDesc, propNew *resolver.Prop) bool {
-	propType := func(t string) string {
-		t = strings.ToLower(t)
-		// "int" is treated as an alias for "int64"
-		if t == "int" {
-			t = "int64"
-		}
-		return t
-	}
-
-	notNull := func(s string) bool {
-		if strings.ToLower(s) == "yes" {
-			return false
-		}
+	if propType(propNew.DataType) != propType(propExist.Type) ||
+		propNew.NotNull != propNotNull(propExist.Null) ||
+		propNew.Default != propDefault(propExist.Default) {
 		return true
 	}
 
-	defaultValue := func(s string) string {
-		if s == "_EMPTY_" {
-			return ""
-		}
-		if s == "" {
-			return "''"
-		}
-		return s
+	return false
+}
+
+// propType normalizes the data type of the property
+func propType(t string) string {
+	t = strings.ToLower(t)
+	// "int" is treated as an alias for "int64"
+	if t == "int" {
+		t = "int64"
 	}
+	return t
+}
 
-	if propType(propNew.DataType) != propType(propExist.Type) ||
-		propNew.NotNull != notNull(propExist.Null) ||
-		propNew.Default != defaultValue(propExist.Default) {
-		return true
+// propNotNull converts the Null column returned by DESCRIBE TAG/EDGE
+func propNotNull(s string) bool {
+	if strings.ToLower(s) == "yes" {
+		return false
 	}
+	return true
+}
 
-	return false
+// propDefault converts the Default column returned by DESCRIBE TAG/EDGE to the format declared in the struct tag
+func propDefault(s string) string {
+	if s == "_EMPTY_" {
+		return ""
+	}
+	if s == "" {
+		return "''"
+	}
+	return s
 }
 
 // HasVertexTag checks whether a given tag exists in the current graph space.
@@ -437,3 +473,83 @@ func (m *Migrator) DropEdgeIndex(indexName string, ifExists ...bool) error {
 	tx.Statement.DropEdgeIndex(indexName, ifExists...)
 	return tx.Exec()
 }
+
+// spaceDB returns a DB to execute the statements managing graph spaces, which are not bound to the space of the
+// current DB and are always executed on the primary cluster
+func (m *Migrator) spaceDB() *DB {
+	tx := m.db.UsePrimary()
+	tx.space = ""
+	return tx
+}
+
+// HasSpace checks whether the graph space exists.
+func (m *Migrator) HasSpace(spaceName string) (bool, error) {
+	spaces := make([]string, 0)
+	err := m.spaceDB().Raw("SHOW SPACES").
+		FindCol("Name", &spaces)
+	if err != nil {
+		return false, err
+	}
+	for _, space := range spaces {
+		if space == spaceName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SpaceDesc the description of a graph space returned by DESCRIBE SPACE
+type SpaceDesc struct {
+	ID            int64  `norm:"col:ID"`
+	Name          string `norm:"col:Name"`
+	PartitionNum  int    `norm:"col:Partition Number"`
+	ReplicaFactor int    `norm:"col:Replica Factor"`
+	Charset       string `norm:"col:Charset"`
+	Collate       string `norm:"col:Collate"`
+	VIDType       string `norm:"col:Vid Type"`
+	Zones         string `norm:"col:Zones"`
+	Comment       string `norm:"col:Comment"`
+}
+
+// DescSpace returns the description of the graph space, eg: the partition number and the vid type.
+func (m *Migrator) DescSpace(spaceName string) (*SpaceDesc, error) {
+	desc := new(SpaceDesc)
+	err := m.spaceDB().Raw("DESCRIBE SPACE " + spaceName).
+		Take(desc)
+	if err != nil {
+		return nil, err
+	}
+	return desc, nil
+}
+
+// CreateSpace creates a graph space with the options.
+// see more information on the method of the same name in statement.Statement
+func (m *Migrator) CreateSpace(spaceName string, opts clause.SpaceOptions, ifNotExists ...bool) error {
+	tx := m.spaceDB()
+	tx.Statement.CreateSpace(spaceName, opts, ifNotExists...)
+	return tx.Exec()
+}
+
+// CreateSpaceAs creates a graph space with the same schema as the source space.
+// see more information on the method of the same name in statement.Statement
+func (m *Migrator) CreateSpaceAs(spaceName, sourceSpace string, ifNotExists ...bool) error {
+	tx := m.spaceDB()
+	tx.Statement.CreateSpaceAs(spaceName, sourceSpace, ifNotExists...)
+	return tx.Exec()
+}
+
+// DropSpace drops a graph space along with all the schemas and data in it.
+// see more information on the method of the same name in statement.Statement
+func (m *Migrator) DropSpace(spaceName string, ifExists ...bool) error {
+	tx := m.spaceDB()
+	tx.Statement.DropSpace(spaceName, ifExists...)
+	return tx.Exec()
+}
+
+// ClearSpace deletes all the data in a graph space, the schemas are kept.
+// see more information on the method of the same name in statement.Statement
+func (m *Migrator) ClearSpace(spaceName string, ifExists ...bool) error {
+	tx := m.spaceDB()
+	tx.Statement.ClearSpace(spaceName, ifExists...)
+	return tx.Exec()
+}
@@ -0,0 +1,340 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/haysons/norm/resolver"
+	"strconv"
+	"time"
+)
+
+// JobStatus the status of a job of nebula graph, eg: the job rebuilding indexes
+type JobStatus string
+
+const (
+	JobStatusQueue    JobStatus = "QUEUE"
+	JobStatusRunning  JobStatus = "RUNNING"
+	JobStatusFinished JobStatus = "FINISHED"
+	JobStatusFailed   JobStatus = "FAILED"
+	JobStatusStopped  JobStatus = "STOPPED"
+)
+
+// indexStatus the status of the job rebuilding the index, returned by SHOW TAG/EDGE INDEX STATUS
+type indexStatus struct {
+	Name   string    `norm:"col:Name"`
+	Status JobStatus `norm:"col:Index Status"`
+}
+
+// WaitIndexReady waits until the indexes of tags or edges can be rebuilt, that is they are listed by
+// SHOW TAG/EDGE INDEXES, no job is rebuilding them, and two heartbeat cycles have passed since then, so that all
+// the storage services are aware of them. It returns the error of the context if it is done before that.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+//	defer cancel()
+//	err := db.Migrator().WaitIndexReady(ctx, "player_name_index")
+func (m *Migrator) WaitIndexReady(ctx context.Context, indexNames ...string) error {
+	if len(indexNames) == 0 || m.db.isDryRun() {
+		return nil
+	}
+	var readyAt time.Time
+	err := m.poll(ctx, func() (bool, error) {
+		ready, err := m.indexesReady(ctx, indexNames)
+		if err != nil || !ready {
+			readyAt = time.Time{}
+			return false, err
+		}
+		if readyAt.IsZero() {
+			readyAt = time.Now()
+		}
+		return time.Since(readyAt) >= 2*m.heartbeatInterval, nil
+	})
+	if err != nil {
+		return fmt.Errorf("norm: wait for indexes %v to be ready failed: %w", indexNames, err)
+	}
+	return nil
+}
+
+// indexesReady reports whether the indexes exist and are not being rebuilt
+func (m *Migrator) indexesReady(ctx context.Context, indexNames []string) (bool, error) {
+	exists := make(map[string]bool)
+	for _, nGQL := range []string{"SHOW TAG INDEXES", "SHOW EDGE INDEXES"} {
+		names := make([]string, 0)
+		if err := m.primary(ctx).Raw(nGQL).FindCol("Index Name", &names); err != nil {
+			return false, err
+		}
+		for _, name := range names {
+			exists[name] = true
+		}
+	}
+	statuses := make(map[string]JobStatus)
+	for _, nGQL := range []string{"SHOW TAG INDEX STATUS", "SHOW EDGE INDEX STATUS"} {
+		rows := make([]*indexStatus, 0)
+		if err := m.primary(ctx).Raw(nGQL).Find(&rows); err != nil {
+			return false, err
+		}
+		for _, row := range rows {
+			statuses[row.Name] = row.Status
+		}
+	}
+	for _, name := range indexNames {
+		if !exists[name] {
+			return false, nil
+		}
+		if status := statuses[name]; status == JobStatusQueue || status == JobStatusRunning {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// RebuildAndWait rebuilds the indexes of tags or edges, and waits for the rebuild job to finish. an error wrapping
+// ErrJobFailed is returned if the job failed or was stopped. Note that the newly created indexes should be ready
+// before they are rebuilt, see WaitIndexReady.
+//
+//	err := db.Migrator().RebuildAndWait(ctx, resolver.IndexTypeTag, "player_name_index")
+func (m *Migrator) RebuildAndWait(ctx context.Context, indexType resolver.IndexType, indexNames ...string) error {
+	if len(indexNames) == 0 {
+		return nil
+	}
+	tx := m.primary(ctx)
+	switch indexType {
+	case resolver.IndexTypeTag:
+		tx.Statement.RebuildVertexTagIndexes(indexNames...)
+	case resolver.IndexTypeEdge:
+		tx.Statement.RebuildEdgeIndexes(indexNames...)
+	default:
+		return fmt.Errorf("norm: %w, invalid index type %d", ErrInvalidValue, indexType)
+	}
+	jobID, err := m.newJobID(tx)
+	if err != nil {
+		return fmt.Errorf("norm: rebuild indexes %v failed: %w", indexNames, err)
+	}
+	_, err = m.WaitJob(ctx, jobID)
+	return err
+}
+
+// JobType the type of the job submitted by SUBMIT JOB
+type JobType string
+
+const (
+	JobTypeCompact           JobType = "COMPACT"
+	JobTypeFlush             JobType = "FLUSH"
+	JobTypeStats             JobType = "STATS"
+	JobTypeBalanceLeader     JobType = "BALANCE LEADER"
+	JobTypeBalanceData       JobType = "BALANCE DATA"
+	JobTypeBalanceInZone     JobType = "BALANCE IN ZONE"
+	JobTypeBalanceAcrossZone JobType = "BALANCE ACROSS ZONE"
+)
+
+// Job the job of nebula graph returned by SHOW JOB and SHOW JOBS, the tasks are only returned by SHOW JOB
+type Job struct {
+	ID        int64
+	Command   string
+	Status    JobStatus
+	StartTime time.Time // zero if the job has not started
+	StopTime  time.Time // zero if the job has not stopped
+	ErrorCode string
+	Tasks     []*JobTask
+}
+
+// JobTask the task of the job executed on a storage host
+type JobTask struct {
+	ID        int64
+	Host      string
+	Status    JobStatus
+	StartTime time.Time
+	StopTime  time.Time
+	ErrorCode string
+}
+
+// jobRow the row returned by SHOW JOB, the first row is the job, followed by the tasks and a summary row. the id
+// and the times are scanned into any, as they are strings in the summary row and empty if not set.
+type jobRow struct {
+	ID        any       `norm:"col:Job Id(TaskId)"`
+	Command   string    `norm:"col:Command(Dest)"`
+	Status    JobStatus `norm:"col:Status"`
+	StartTime any       `norm:"col:Start Time"`
+	StopTime  any       `norm:"col:Stop Time"`
+	ErrorCode string    `norm:"col:Error Code"`
+}
+
+// jobsRow the row returned by SHOW JOBS
+type jobsRow struct {
+	ID        int64     `norm:"col:Job Id"`
+	Command   string    `norm:"col:Command"`
+	Status    JobStatus `norm:"col:Status"`
+	StartTime any       `norm:"col:Start Time"`
+	StopTime  any       `norm:"col:Stop Time"`
+	ErrorCode string    `norm:"col:Error Code"`
+}
+
+// SubmitJob submits the job in the current graph space, and returns the id of the job
+//
+//	jobID, err := db.Migrator().SubmitJob(norm.JobTypeStats)
+func (m *Migrator) SubmitJob(jobType JobType) (int64, error) {
+	jobID, err := m.newJobID(m.primary(m.db.Context()).Raw("SUBMIT JOB " + string(jobType)))
+	if err != nil {
+		return 0, fmt.Errorf("norm: submit job %s failed: %w", jobType, err)
+	}
+	return jobID, nil
+}
+
+// newJobID executes the statement creating a job and returns the id of the job, which is zero in dry run mode
+func (m *Migrator) newJobID(tx *DB) (int64, error) {
+	jobIDs := make([]int64, 0, 1)
+	if err := tx.FindCol("New Job Id", &jobIDs); err != nil {
+		return 0, err
+	}
+	if len(jobIDs) == 0 {
+		if m.db.isDryRun() {
+			return 0, nil
+		}
+		return 0, errors.New("no job id returned")
+	}
+	return jobIDs[0], nil
+}
+
+// ShowJob returns the job with its tasks
+func (m *Migrator) ShowJob(jobID int64) (*Job, error) {
+	return m.showJob(m.db.Context(), jobID)
+}
+
+func (m *Migrator) showJob(ctx context.Context, jobID int64) (*Job, error) {
+	rows := make([]*jobRow, 0)
+	if err := m.primary(ctx).Raw("SHOW JOB " + strconv.FormatInt(jobID, 10)).Find(&rows); err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("norm: job %d not found", jobID)
+	}
+	job := &Job{
+		Command:   rows[0].Command,
+		Status:    rows[0].Status,
+		StartTime: jobTime(rows[0].StartTime),
+		StopTime:  jobTime(rows[0].StopTime),
+		ErrorCode: rows[0].ErrorCode,
+	}
+	job.ID, _ = rows[0].ID.(int64)
+	for _, row := range rows[1:] {
+		taskID, ok := row.ID.(int64)
+		if !ok {
+			// the summary row
+			continue
+		}
+		job.Tasks = append(job.Tasks, &JobTask{
+			ID:        taskID,
+			Host:      row.Command,
+			Status:    row.Status,
+			StartTime: jobTime(row.StartTime),
+			StopTime:  jobTime(row.StopTime),
+			ErrorCode: row.ErrorCode,
+		})
+	}
+	return job, nil
+}
+
+// ShowJobs returns the jobs in the current graph space, without their tasks
+func (m *Migrator) ShowJobs() ([]*Job, error) {
+	rows := make([]*jobsRow, 0)
+	if err := m.primary(m.db.Context()).Raw("SHOW JOBS").Find(&rows); err != nil {
+		return nil, err
+	}
+	jobs := make([]*Job, 0, len(rows))
+	for _, row := range rows {
+		jobs = append(jobs, &Job{
+			ID:        row.ID,
+			Command:   row.Command,
+			Status:    row.Status,
+			StartTime: jobTime(row.StartTime),
+			StopTime:  jobTime(row.StopTime),
+			ErrorCode: row.ErrorCode,
+		})
+	}
+	return jobs, nil
+}
+
+// StopJob stops the job which is queued or running
+func (m *Migrator) StopJob(jobID int64) error {
+	return m.primary(m.db.Context()).Raw("STOP JOB " + strconv.FormatInt(jobID, 10)).Exec()
+}
+
+// RecoverJob re-executes the failed or stopped jobs, all of them in the current graph space if no id is given,
+// and returns the number of the jobs recovered
+func (m *Migrator) RecoverJob(jobIDs ...int64) (int64, error) {
+	nGQL := "RECOVER JOB"
+	for i, jobID := range jobIDs {
+		if i > 0 {
+			nGQL += ","
+		}
+		nGQL += " " + strconv.FormatInt(jobID, 10)
+	}
+	nums := make([]int64, 0, 1)
+	if err := m.primary(m.db.Context()).Raw(nGQL).FindCol("Recovered job num", &nums); err != nil {
+		return 0, err
+	}
+	if len(nums) == 0 {
+		return 0, nil
+	}
+	return nums[0], nil
+}
+
+// WaitJob waits for the job to finish and returns it, an error wrapping ErrJobFailed is returned if the job failed
+// or was stopped, and the error of the context if it is done before that.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+//	defer cancel()
+//	job, err := db.Migrator().WaitJob(ctx, jobID)
+func (m *Migrator) WaitJob(ctx context.Context, jobID int64) (*Job, error) {
+	if m.db.isDryRun() {
+		return nil, nil
+	}
+	var job *Job
+	err := m.poll(ctx, func() (bool, error) {
+		var err error
+		if job, err = m.showJob(ctx, jobID); err != nil {
+			return false, err
+		}
+		switch job.Status {
+		case JobStatusFinished:
+			return true, nil
+		case JobStatusFailed, JobStatusStopped:
+			return false, fmt.Errorf("norm: %w, job %d is %s", ErrJobFailed, jobID, job.Status)
+		default:
+			return false, nil
+		}
+	})
+	if err != nil {
+		return job, fmt.Errorf("norm: wait for job %d failed: %w", jobID, err)
+	}
+	return job, nil
+}
+
+// jobTime returns the time scanned from the start or stop time of the job, zero if it is not set
+func jobTime(v any) time.Time {
+	t, _ := v.(time.Time)
+	return t
+}
+
+// primary returns a DB with the context to execute the statements on the primary cluster, which is where the
+// schemas are changed
+func (m *Migrator) primary(ctx context.Context) *DB {
+	return m.db.WithContext(ctx).UsePrimary()
+}
+
+// poll calls done every poll interval until it returns true or an error, or the context is done
+func (m *Migrator) poll(ctx context.Context, done func() (bool, error)) error {
+	for {
+		ok, err := done()
+		if err != nil || ok {
+			return err
+		}
+		timer := time.NewTimer(m.pollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package norm
+
+import (
+	"github.com/haysons/norm/clause"
+	"github.com/haysons/norm/resolver"
+	"reflect"
+)
+
+// SchemaPlan the changes AutoMigrateVertexes and AutoMigrateEdges would make, returned by Plan
+type SchemaPlan struct {
+	Tags    []*SchemaChange // the tags to create or alter
+	Edges   []*SchemaChange // the edge types to create or alter
+	Indexes []*IndexChange  // the indexes to create
+	NGQL    []string        // the statements that would be executed in order
+}
+
+// Empty reports whether there is no change to make
+func (p *SchemaPlan) Empty() bool {
+	return len(p.NGQL) == 0
+}
+
+// SchemaChange the changes of a tag or an edge type. all the props are in AddProps if it is to be created.
+type SchemaChange struct {
+	Name        string
+	Create      bool
+	AddProps    []*PropChange
+	ChangeProps []*PropChange
+	DBOnlyProps []*PropDef // the props only exist in the DB, which are never dropped
+}
+
+// altered reports whether the existing tag or edge type should be altered
+func (c *SchemaChange) altered() bool {
+	return !c.Create && (len(c.AddProps) > 0 || len(c.ChangeProps) > 0)
+}
+
+// alterOperate returns the operation altering the tag or edge type
+func (c *SchemaChange) alterOperate() clause.AlterOperate {
+	alterOp := clause.AlterOperate{}
+	for _, prop := range c.AddProps {
+		alterOp.AddProps = append(alterOp.AddProps, prop.Name)
+	}
+	for _, prop := range c.ChangeProps {
+		alterOp.ChangeProps = append(alterOp.ChangeProps, prop.Name)
+	}
+	return alterOp
+}
+
+// PropChange the change of a prop, Old is nil if the prop is to be added
+type PropChange struct {
+	Name string
+	Old  *PropDef
+	New  *PropDef
+}
+
+// PropDef the definition of a prop
+type PropDef struct {
+	Name    string
+	Type    string
+	NotNull bool
+	Default string
+}
+
+// IndexChange the index to create
+type IndexChange struct {
+	Name   string
+	Type   resolver.IndexType
+	Target string // the name of the tag or edge type indexed
+}
+
+// diffProps compares the props declared in the struct with the ones in the DB
+func (m *Migrator) diffProps(name string, propsDB []*PropDesc, props []*resolver.Prop) *SchemaChange {
+	change := &SchemaChange{Name: name}
+	propsExist := make(map[string]*PropDesc)
+	for _, prop := range propsDB {
+		propsExist[prop.Field] = prop
+	}
+	propsDeclared := make(map[string]bool)
+	for _, propNew := range props {
+		propsDeclared[propNew.Name] = true
+		// Add the property if it does not exist
+		propExist, ok := propsExist[propNew.Name]
+		if !ok {
+			change.AddProps = append(change.AddProps, &PropChange{Name: propNew.Name, New: newPropDef(propNew)})
+			continue
+		}
+		// Apply change if the property differs from existing definition
+		if m.isPropChanged(propExist, propNew) {
+			change.ChangeProps = append(change.ChangeProps, &PropChange{
+				Name: propNew.Name,
+				Old:  existPropDef(propExist),
+				New:  newPropDef(propNew),
+			})
+		}
+	}
+	for _, prop := range propsDB {
+		if !propsDeclared[prop.Field] {
+			change.DBOnlyProps = append(change.DBOnlyProps, existPropDef(prop))
+		}
+	}
+	return change
+}
+
+func newPropDef(prop *resolver.Prop) *PropDef {
+	return &PropDef{Name: prop.Name, Type: prop.DataType, NotNull: prop.NotNull, Default: prop.Default}
+}
+
+func existPropDef(prop *PropDesc) *PropDef {
+	return &PropDef{Name: prop.Field, Type: prop.Type, NotNull: propNotNull(prop.Null), Default: propDefault(prop.Default)}
+}
+
+// Plan returns the changes AutoMigrateVertexes and AutoMigrateEdges would make with the same comparison, and the
+// statements that would be executed, without applying them. so the schema changes can be reviewed beforehand.
+//
+//	plan, err := db.Migrator().Plan([]any{Player{}}, []any{Follow{}})
+//	for _, nGQL := range plan.NGQL {
+//		fmt.Println(nGQL)
+//	}
+func (m *Migrator) Plan(vertexes []any, edges []any) (*SchemaPlan, error) {
+	plan := new(SchemaPlan)
+	planned := make(map[string]bool)
+	for _, vertex := range vertexes {
+		vertexSchema, err := resolver.ParseVertex(reflect.TypeOf(vertex))
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range vertexSchema.GetTags() {
+			if planned["tag:"+tag.TagName] {
+				continue
+			}
+			planned["tag:"+tag.TagName] = true
+			if err = m.planVertexTag(plan, tag); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, edge := range edges {
+		edgeSchema, err := resolver.ParseEdge(reflect.TypeOf(edge))
+		if err != nil {
+			return nil, err
+		}
+		if planned["edge:"+edgeSchema.GetTypeName()] {
+			continue
+		}
+		planned["edge:"+edgeSchema.GetTypeName()] = true
+		if err = m.planEdge(plan, edgeSchema); err != nil {
+			return nil, err
+		}
+	}
+	return plan, nil
+}
+
+func (m *Migrator) planVertexTag(plan *SchemaPlan, tag *resolver.VertexTag) error {
+	hasTag, err := m.HasVertexTag(tag.TagName)
+	if err != nil {
+		return err
+	}
+	var change *SchemaChange
+	tx := m.db.getInstance()
+	if !hasTag {
+		change = m.diffProps(tag.TagName, nil, tag.GetProps())
+		change.Create = true
+		tx.Statement.CreateVertexTags(tag, true)
+	} else {
+		tagProps, err := m.DescVertexTag(tag.TagName)
+		if err != nil {
+			return err
+		}
+		change = m.diffProps(tag.TagName, tagProps, tag.GetProps())
+		tx.Statement.AlterVertexTag(tag, change.alterOperate())
+	}
+	if change.Create || change.altered() {
+		if err = plan.addNGQL(tx); err != nil {
+			return err
+		}
+	}
+	if change.Create || change.altered() || len(change.DBOnlyProps) > 0 {
+		plan.Tags = append(plan.Tags, change)
+	}
+
+	created := make([]string, 0)
+	for _, index := range tag.GetIndexes() {
+		hasIndex, err := m.HasVertexTagIndex(index.Name)
+		if err != nil {
+			return err
+		}
+		if hasIndex {
+			continue
+		}
+		plan.Indexes = append(plan.Indexes, &IndexChange{Name: index.Name, Type: index.Type, Target: tag.TagName})
+		tx = m.db.getInstance()
+		tx.Statement.CreateVertexTagsIndex(index, true)
+		if err = plan.addNGQL(tx); err != nil {
+			return err
+		}
+		created = append(created, index.Name)
+	}
+	if m.rebuildIndexes && len(created) > 0 {
+		tx = m.db.getInstance()
+		tx.Statement.RebuildVertexTagIndexes(created...)
+		return plan.addNGQL(tx)
+	}
+	return nil
+}
+
+func (m *Migrator) planEdge(plan *SchemaPlan, edge *resolver.EdgeSchema) error {
+	hasEdge, err := m.HasEdge(edge.GetTypeName())
+	if err != nil {
+		return err
+	}
+	var change *SchemaChange
+	tx := m.db.getInstance()
+	if !hasEdge {
+		change = m.diffProps(edge.GetTypeName(), nil, edge.GetProps())
+		change.Create = true
+		tx.Statement.CreateEdge(edge, true)
+	} else {
+		edgeProps, err := m.DescEdge(edge.GetTypeName())
+		if err != nil {
+			return err
+		}
+		change = m.diffProps(edge.GetTypeName(), edgeProps, edge.GetProps())
+		tx.Statement.AlterEdge(edge, change.alterOperate())
+	}
+	if change.Create || change.altered() {
+		if err = plan.addNGQL(tx); err != nil {
+			return err
+		}
+	}
+	if change.Create || change.altered() || len(change.DBOnlyProps) > 0 {
+		plan.Edges = append(plan.Edges, change)
+	}
+
+	created := make([]string, 0)
+	for _, index := range edge.GetIndexes() {
+		hasIndex, err := m.HasEdgeIndex(index.Name)
+		if err != nil {
+			return err
+		}
+		if hasIndex {
+			continue
+		}
+		plan.Indexes = append(plan.Indexes, &IndexChange{Name: index.Name, Type: index.Type, Target: edge.GetTypeName()})
+		tx = m.db.getInstance()
+		tx.Statement.CreateEdgeIndex(index, true)
+		if err = plan.addNGQL(tx); err != nil {
+			return err
+		}
+		created = append(created, index.Name)
+	}
+	if m.rebuildIndexes && len(created) > 0 {
+		tx = m.db.getInstance()
+		tx.Statement.RebuildEdgeIndexes(created...)
+		return plan.addNGQL(tx)
+	}
+	return nil
+}
+
+// addNGQL renders the statement built by the DB without executing it
+func (p *SchemaPlan) addNGQL(tx *DB) error {
+	nGQL, err := tx.NGQL()
+	if err != nil {
+		return err
+	}
+	p.NGQL = append(p.NGQL, nGQL)
+	return nil
+}
@@ -0,0 +1,414 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"github.com/haysons/norm/clause"
+	"github.com/haysons/norm/logger"
+	"github.com/haysons/norm/resolver"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMigratorSpace(t *testing.T) {
+	primary, replica := &fakeExecutor{}, &fakeExecutor{}
+	db, err := OpenWithExecutor(&Config{SpaceName: "test"}, primary, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+	db.replicas = &replicaSet{executors: []Executor{replica}}
+	m := db.Space("tenant").Migrator()
+
+	assert.NoError(t, m.CreateSpace("tenant", clause.SpaceOptions{PartitionNum: 10, ReplicaFactor: 1, VIDType: resolver.VIDTypeString, FixedStringLength: 32}, true))
+	assert.NoError(t, m.CreateSpaceAs("tenant_copy", "tenant"))
+	assert.NoError(t, m.ClearSpace("tenant_copy", true))
+	assert.NoError(t, m.DropSpace("tenant_copy"))
+	assert.Error(t, m.CreateSpace("tenant", clause.SpaceOptions{}))
+
+	primary.res = newResult(t, []string{"Name"}, []any{"test"}, []any{"tenant"})
+	has, err := m.HasSpace("tenant")
+	assert.NoError(t, err)
+	assert.True(t, has)
+	has, err = m.HasSpace("tenant_copy")
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	cols := []string{"ID", "Name", "Partition Number", "Replica Factor", "Charset", "Collate", "Vid Type", "Zones", "Comment"}
+	primary.res = newResult(t, cols, []any{1, "tenant", 10, 1, "utf8", "utf8_bin", "FIXED_STRING(32)", "default_zone", ""})
+	desc, err := m.DescSpace("tenant")
+	assert.NoError(t, err)
+	assert.Equal(t, &SpaceDesc{ID: 1, Name: "tenant", PartitionNum: 10, ReplicaFactor: 1, Charset: "utf8", Collate: "utf8_bin", VIDType: "FIXED_STRING(32)", Zones: "default_zone"}, desc)
+
+	// the statements are not bound to the space of the DB, and are executed on the primary cluster
+	assert.Equal(t, []string{
+		"CREATE SPACE IF NOT EXISTS tenant(partition_num = 10, replica_factor = 1, vid_type = FIXED_STRING(32));",
+		"CREATE SPACE tenant_copy AS tenant;",
+		"CLEAR SPACE IF EXISTS tenant_copy;",
+		"DROP SPACE tenant_copy;",
+		"SHOW SPACES",
+		"SHOW SPACES",
+		"DESCRIBE SPACE tenant",
+	}, primary.stmts)
+	assert.Empty(t, replica.stmts)
+}
+
+func TestMigratorPrimary(t *testing.T) {
+	primary, replica := &fakeExecutor{res: newResult(t, nil)}, &fakeExecutor{res: newResult(t, nil)}
+	db, err := OpenWithExecutor(&Config{}, primary, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+	db.replicas = &replicaSet{executors: []Executor{replica}}
+
+	// the schemas are read from the primary cluster where they are changed
+	m := db.Migrator()
+	assert.NoError(t, m.AutoMigrateVertexes(indexedPlayer{}))
+	_, err = m.DescVertexTag("player")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"SHOW TAGS",
+		"CREATE TAG IF NOT EXISTS player(name string);",
+		"SHOW TAG INDEXES",
+		"CREATE TAG INDEX IF NOT EXISTS idx_player_name ON player(name(10));",
+		"DESCRIBE TAG player",
+	}, primary.stmts)
+	assert.Empty(t, replica.stmts)
+
+	// the DB creating the Migrator is not affected
+	assert.NoError(t, db.Raw("SHOW TAGS").Exec())
+	assert.Equal(t, []string{"SHOW TAGS"}, replica.stmts)
+}
+
+type indexedPlayer struct {
+	VID  string `norm:"vertex_id"`
+	Name string `norm:"prop:name;index:,length:10"`
+}
+
+func (p indexedPlayer) VertexID() string {
+	return p.VID
+}
+
+func (p indexedPlayer) VertexTagName() string {
+	return "player"
+}
+
+// indexCluster simulates the statements about tag indexes and jobs of nebula graph
+type indexCluster struct {
+	t           *testing.T
+	indexes     []string          // the tag indexes created
+	indexStatus map[string]string // the status of the jobs rebuilding the indexes
+	jobStatus   []string          // the status returned by SHOW JOB in order, the last one repeats
+	stmts       []string          // the statements executed except SHOW TAG/EDGE INDEXES and INDEX STATUS
+	errs        map[string]error  // the errors returned for the statements
+}
+
+func (c *indexCluster) execute(stmt string) (*nebula.ResultSet, error) {
+	if err := c.errs[stmt]; err != nil {
+		return nil, err
+	}
+	switch {
+	case stmt == "SHOW TAG INDEXES":
+		rows := make([][]any, 0, len(c.indexes))
+		for _, name := range c.indexes {
+			rows = append(rows, []any{name, "player"})
+		}
+		return newResult(c.t, []string{"Index Name", "By Tag"}, rows...), nil
+	case stmt == "SHOW EDGE INDEXES":
+		return newResult(c.t, []string{"Index Name", "By Edge"}), nil
+	case stmt == "SHOW TAG INDEX STATUS":
+		rows := make([][]any, 0, len(c.indexStatus))
+		for name, status := range c.indexStatus {
+			rows = append(rows, []any{name, status})
+		}
+		return newResult(c.t, []string{"Name", "Index Status"}, rows...), nil
+	case stmt == "SHOW EDGE INDEX STATUS":
+		return newResult(c.t, []string{"Name", "Index Status"}), nil
+	}
+	c.stmts = append(c.stmts, stmt)
+	switch {
+	case stmt == "SHOW TAGS":
+		return newResult(c.t, []string{"Name"}), nil
+	case strings.HasPrefix(stmt, "CREATE TAG INDEX"):
+		c.indexes = append(c.indexes, "idx_player_name")
+		return newResult(c.t, nil), nil
+	case strings.HasPrefix(stmt, "REBUILD TAG INDEX"):
+		return newResult(c.t, []string{"New Job Id"}, []any{7}), nil
+	case strings.HasPrefix(stmt, "SHOW JOB"):
+		status := c.jobStatus[0]
+		if len(c.jobStatus) > 1 {
+			c.jobStatus = c.jobStatus[1:]
+		}
+		return newResult(c.t, []string{"Job Id(TaskId)", "Command(Dest)", "Status"}, []any{7, "REBUILD_TAG_INDEX", status}), nil
+	}
+	return newResult(c.t, nil), nil
+}
+
+func (c *indexCluster) migrator(opts ...MigratorOption) *Migrator {
+	db, err := OpenWithExecutor(&Config{}, funcExecutor(c.execute), WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(c.t, err)
+	opts = append([]MigratorOption{WithPollInterval(time.Millisecond), WithHeartbeatInterval(0)}, opts...)
+	return db.Migrator(opts...)
+}
+
+func TestMigratorWaitIndexReady(t *testing.T) {
+	c := &indexCluster{t: t, indexStatus: map[string]string{"idx_player_name": "RUNNING"}}
+	m := c.migrator(WithHeartbeatInterval(5 * time.Millisecond))
+
+	// the index does not exist
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := m.WaitIndexReady(ctx, "idx_player_name")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// the index is being rebuilt
+	c.indexes = []string{"idx_player_name"}
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = m.WaitIndexReady(ctx, "idx_player_name")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// the index is ready after two heartbeat cycles
+	c.indexStatus["idx_player_name"] = "FINISHED"
+	start := time.Now()
+	assert.NoError(t, m.WaitIndexReady(context.Background(), "idx_player_name"))
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	assert.NoError(t, m.WaitIndexReady(context.Background()))
+
+	c.errs = map[string]error{"SHOW TAG INDEXES": errors.New("network error")}
+	assert.Error(t, m.WaitIndexReady(context.Background(), "idx_player_name"))
+}
+
+func TestMigratorRebuildAndWait(t *testing.T) {
+	c := &indexCluster{t: t, jobStatus: []string{"QUEUE", "RUNNING", "FINISHED"}}
+	m := c.migrator()
+	assert.NoError(t, m.RebuildAndWait(context.Background(), resolver.IndexTypeTag, "idx_player_name"))
+	assert.Equal(t, []string{"REBUILD TAG INDEX idx_player_name;", "SHOW JOB 7", "SHOW JOB 7", "SHOW JOB 7"}, c.stmts)
+
+	c = &indexCluster{t: t, jobStatus: []string{"RUNNING", "FAILED"}}
+	m = c.migrator()
+	err := m.RebuildAndWait(context.Background(), resolver.IndexTypeTag, "idx_player_name")
+	assert.True(t, errors.Is(err, ErrJobFailed))
+
+	c = &indexCluster{t: t, jobStatus: []string{"RUNNING"}}
+	m = c.migrator()
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = m.RebuildAndWait(ctx, resolver.IndexTypeTag, "idx_player_name")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	err = m.RebuildAndWait(context.Background(), resolver.IndexType(100), "idx_player_name")
+	assert.True(t, errors.Is(err, ErrInvalidValue))
+	assert.NoError(t, m.RebuildAndWait(context.Background(), resolver.IndexTypeTag))
+}
+
+func TestMigratorAutoRebuildIndexes(t *testing.T) {
+	c := &indexCluster{t: t, jobStatus: []string{"RUNNING", "FINISHED"}}
+	assert.NoError(t, c.migrator().AutoMigrateVertexes(indexedPlayer{}))
+	assert.Equal(t, []string{
+		"SHOW TAGS",
+		"CREATE TAG IF NOT EXISTS player(name string);",
+		"CREATE TAG INDEX IF NOT EXISTS idx_player_name ON player(name(10));",
+	}, c.stmts)
+
+	c = &indexCluster{t: t, jobStatus: []string{"RUNNING", "FINISHED"}}
+	assert.NoError(t, c.migrator(WithRebuildIndexes()).AutoMigrateVertexes(indexedPlayer{}))
+	assert.Equal(t, []string{
+		"SHOW TAGS",
+		"CREATE TAG IF NOT EXISTS player(name string);",
+		"CREATE TAG INDEX IF NOT EXISTS idx_player_name ON player(name(10));",
+		"REBUILD TAG INDEX idx_player_name;",
+		"SHOW JOB 7",
+		"SHOW JOB 7",
+	}, c.stmts)
+
+	c = &indexCluster{t: t, jobStatus: []string{"STOPPED"}}
+	err := c.migrator(WithRebuildIndexes()).AutoMigrateVertexes(indexedPlayer{})
+	assert.True(t, errors.Is(err, ErrJobFailed))
+
+	// the indexes are neither rebuilt nor waited for in dry run mode of Config
+	executor := &fakeExecutor{}
+	db, err := OpenWithExecutor(&Config{DryRun: true}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+	assert.NoError(t, db.Migrator(WithRebuildIndexes()).AutoMigrateVertexes(indexedPlayer{}))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, db.Migrator().WaitIndexReady(ctx, "idx_player_name"))
+	assert.Empty(t, executor.stmts)
+}
+
+func TestMigratorJob(t *testing.T) {
+	start := time.Date(2025, 3, 1, 8, 14, 45, 0, time.UTC)
+	stop := start.Add(time.Minute)
+	jobCols := []string{"Job Id(TaskId)", "Command(Dest)", "Status", "Start Time", "Stop Time", "Error Code"}
+	stmts := make([]string, 0)
+	executor := funcExecutor(func(stmt string) (*nebula.ResultSet, error) {
+		stmts = append(stmts, stmt)
+		switch stmt {
+		case "SUBMIT JOB STATS":
+			return newResult(t, []string{"New Job Id"}, []any{8}), nil
+		case "SHOW JOB 8":
+			return newResult(t, jobCols,
+				[]any{8, "STATS", "FINISHED", start, stop, "SUCCEEDED"},
+				[]any{0, "storaged0:9779", "FINISHED", start, stop, "SUCCEEDED"},
+				[]any{"Total:1", "Succeeded:1", "Failed:0", "In Progress:0", "", ""},
+			), nil
+		case "SHOW JOB 9":
+			return newResult(t, jobCols, []any{9, "COMPACT", "STOPPED", start, stop, "SUCCEEDED"}), nil
+		case "SHOW JOBS":
+			return newResult(t, []string{"Job Id", "Command", "Status", "Start Time", "Stop Time", "Error Code"},
+				[]any{9, "COMPACT", "QUEUE", nil, nil, "SUCCEEDED"},
+				[]any{8, "STATS", "FINISHED", start, stop, "SUCCEEDED"},
+			), nil
+		case "STOP JOB 9":
+			return newResult(t, []string{"Result"}, []any{"Job stopped"}), nil
+		case "RECOVER JOB 9, 10":
+			return newResult(t, []string{"Recovered job num"}, []any{2}), nil
+		}
+		return nil, errors.New("unexpected statement " + stmt)
+	})
+	db, err := OpenWithExecutor(&Config{}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+	m := db.Migrator(WithPollInterval(time.Millisecond))
+
+	jobID, err := m.SubmitJob(JobTypeStats)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), jobID)
+	_, err = m.SubmitJob(JobTypeCompact)
+	assert.Error(t, err)
+
+	job, err := m.ShowJob(8)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), job.ID)
+	assert.Equal(t, "STATS", job.Command)
+	assert.Equal(t, JobStatusFinished, job.Status)
+	assert.True(t, job.StartTime.Equal(start))
+	assert.True(t, job.StopTime.Equal(stop))
+	assert.Equal(t, "SUCCEEDED", job.ErrorCode)
+	assert.Len(t, job.Tasks, 1)
+	assert.Equal(t, int64(0), job.Tasks[0].ID)
+	assert.Equal(t, "storaged0:9779", job.Tasks[0].Host)
+	assert.True(t, job.Tasks[0].StopTime.Equal(stop))
+
+	jobs, err := m.ShowJobs()
+	assert.NoError(t, err)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, JobStatusQueue, jobs[0].Status)
+	assert.True(t, jobs[0].StartTime.IsZero())
+	assert.True(t, jobs[1].StartTime.Equal(start))
+
+	assert.NoError(t, m.StopJob(9))
+	num, err := m.RecoverJob(9, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), num)
+
+	job, err = m.WaitJob(context.Background(), 8)
+	assert.NoError(t, err)
+	assert.Equal(t, JobStatusFinished, job.Status)
+	job, err = m.WaitJob(context.Background(), 9)
+	assert.True(t, errors.Is(err, ErrJobFailed))
+	assert.Equal(t, JobStatusStopped, job.Status)
+
+	// the job statements are not executed in dry run mode
+	stmts = stmts[:0]
+	m = db.DryRun().Migrator()
+	jobID, err = m.SubmitJob(JobTypeFlush)
+	assert.NoError(t, err)
+	assert.Zero(t, jobID)
+	job, err = m.WaitJob(context.Background(), jobID)
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+	assert.Empty(t, stmts)
+
+	db, err = OpenWithExecutor(&Config{DryRun: true}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+	m = db.Migrator()
+	jobID, err = m.SubmitJob(JobTypeFlush)
+	assert.NoError(t, err)
+	assert.Zero(t, jobID)
+	job, err = m.WaitJob(context.Background(), jobID)
+	assert.NoError(t, err)
+	assert.Nil(t, job)
+	assert.Empty(t, stmts)
+}
+
+type planPlayer struct {
+	VID  string `norm:"vertex_id"`
+	Name string `norm:"prop:name;index:,length:10"`
+	Age  int    `norm:"prop:age;not_null;default:0"`
+}
+
+func (p planPlayer) VertexID() string {
+	return p.VID
+}
+
+func (p planPlayer) VertexTagName() string {
+	return "player"
+}
+
+type planFollow struct {
+	SrcID  string `norm:"edge_src_id"`
+	DstID  string `norm:"edge_dst_id"`
+	Degree int    `norm:"prop:degree;index"`
+}
+
+func (f planFollow) EdgeTypeName() string {
+	return "follow"
+}
+
+func TestMigratorPlan(t *testing.T) {
+	primary := &fakeExecutor{}
+	executor := funcExecutor(func(stmt string) (*nebula.ResultSet, error) {
+		switch stmt {
+		case "SHOW TAGS":
+			return newResult(t, []string{"Name"}, []any{"player"}), nil
+		case "DESCRIBE TAG player":
+			return newResult(t, []string{"Field", "Type", "Null", "Default", "Comment"},
+				[]any{"name", "string", "YES", nil, ""},
+				[]any{"age", "int64", "YES", nil, ""},
+				[]any{"nickname", "string", "NO", "", ""},
+			), nil
+		case "SHOW TAG INDEXES", "SHOW EDGE INDEXES":
+			return newResult(t, []string{"Index Name"}), nil
+		case "SHOW EDGES":
+			return newResult(t, []string{"Name"}), nil
+		}
+		return primary.Execute(stmt)
+	})
+	db, err := OpenWithExecutor(&Config{}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+
+	plan, err := db.Migrator(WithRebuildIndexes()).Plan([]any{planPlayer{}, &planPlayer{}}, []any{planFollow{}})
+	assert.NoError(t, err)
+	assert.False(t, plan.Empty())
+	assert.Equal(t, []*SchemaChange{{
+		Name: "player",
+		ChangeProps: []*PropChange{{
+			Name: "age",
+			Old:  &PropDef{Name: "age", Type: "int64"},
+			New:  &PropDef{Name: "age", Type: "int", NotNull: true, Default: "0"},
+		}},
+		DBOnlyProps: []*PropDef{{Name: "nickname", Type: "string", NotNull: true, Default: "''"}},
+	}}, plan.Tags)
+	assert.Equal(t, []*SchemaChange{{
+		Name:     "follow",
+		Create:   true,
+		AddProps: []*PropChange{{Name: "degree", New: &PropDef{Name: "degree", Type: "int"}}},
+	}}, plan.Edges)
+	assert.Equal(t, []*IndexChange{
+		{Name: "idx_player_name", Type: resolver.IndexTypeTag, Target: "player"},
+		{Name: "idx_follow_degree", Type: resolver.IndexTypeEdge, Target: "follow"},
+	}, plan.Indexes)
+	assert.Equal(t, []string{
+		"ALTER TAG player CHANGE (age int NOT NULL DEFAULT 0);",
+		"CREATE TAG INDEX IF NOT EXISTS idx_player_name ON player(name(10));",
+		"REBUILD TAG INDEX idx_player_name;",
+		"CREATE EDGE IF NOT EXISTS follow(degree int);",
+		"CREATE EDGE INDEX IF NOT EXISTS idx_follow_degree ON follow(degree);",
+		"REBUILD EDGE INDEX idx_follow_degree;",
+	}, plan.NGQL)
+	// nothing is executed
+	assert.Empty(t, primary.stmts)
+
+	plan, err = db.Migrator().Plan(nil, nil)
+	assert.NoError(t, err)
+	assert.True(t, plan.Empty())
+}
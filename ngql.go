@@ -7,9 +7,9 @@ import (
 
 // Raw exec nGQL statements natively
 // see more information on the method of the same name in statement.Statement
-func (db *DB) Raw(raw string) (tx *DB) {
+func (db *DB) Raw(raw string, args ...any) (tx *DB) {
 	tx = db.getInstance()
-	tx.Statement.Raw(raw)
+	tx.Statement.Raw(raw, args...)
 	return tx
 }
 
@@ -138,6 +138,7 @@ func (db *DB) Limit(limit int, offset ...int) (tx *DB) {
 func (db *DB) InsertVertex(vertexes any, ifNotExists ...bool) (tx *DB) {
 	tx = db.getInstance()
 	tx.Statement.InsertVertex(vertexes, ifNotExists...)
+	tx.addHookModel(hookEventInsert, vertexes)
 	return
 }
 
@@ -146,6 +147,7 @@ func (db *DB) InsertVertex(vertexes any, ifNotExists ...bool) (tx *DB) {
 func (db *DB) UpdateVertex(vid any, propsUpdate any, opts ...clause.Option) (tx *DB) {
 	tx = db.getInstance()
 	tx.Statement.UpdateVertex(vid, propsUpdate, opts...)
+	tx.addHookModel(hookEventUpdate, propsUpdate)
 	return
 }
 
@@ -154,6 +156,7 @@ func (db *DB) UpdateVertex(vid any, propsUpdate any, opts ...clause.Option) (tx
 func (db *DB) UpsertVertex(vid any, propsUpdate any, opts ...clause.Option) (tx *DB) {
 	tx = db.getInstance()
 	tx.Statement.UpsertVertex(vid, propsUpdate, opts...)
+	tx.addHookModel(hookEventUpdate, propsUpdate)
 	return
 }
 
@@ -162,6 +165,7 @@ func (db *DB) UpsertVertex(vid any, propsUpdate any, opts ...clause.Option) (tx
 func (db *DB) DeleteVertex(vid any, withEdge ...bool) (tx *DB) {
 	tx = db.getInstance()
 	tx.Statement.DeleteVertex(vid, withEdge...)
+	tx.addHookModel(hookEventDelete, vid)
 	return
 }
 
@@ -170,6 +174,7 @@ func (db *DB) DeleteVertex(vid any, withEdge ...bool) (tx *DB) {
 func (db *DB) InsertEdge(edges any, ifNotExists ...bool) (tx *DB) {
 	tx = db.getInstance()
 	tx.Statement.InsertEdge(edges, ifNotExists...)
+	tx.addHookModel(hookEventInsert, edges)
 	return
 }
 
@@ -178,6 +183,7 @@ func (db *DB) InsertEdge(edges any, ifNotExists ...bool) (tx *DB) {
 func (db *DB) UpdateEdge(edge any, propsUpdate any, opts ...clause.Option) (tx *DB) {
 	tx = db.getInstance()
 	tx.Statement.UpdateEdge(edge, propsUpdate, opts...)
+	tx.addHookModel(hookEventUpdate, propsUpdate)
 	return
 }
 
@@ -186,6 +192,7 @@ func (db *DB) UpdateEdge(edge any, propsUpdate any, opts ...clause.Option) (tx *
 func (db *DB) UpsertEdge(edge any, propsUpdate any, opts ...clause.Option) (tx *DB) {
 	tx = db.getInstance()
 	tx.Statement.UpsertEdge(edge, propsUpdate, opts...)
+	tx.addHookModel(hookEventUpdate, propsUpdate)
 	return
 }
 
@@ -194,6 +201,7 @@ func (db *DB) UpsertEdge(edge any, propsUpdate any, opts ...clause.Option) (tx *
 func (db *DB) DeleteEdge(edgeTypeName string, edge any) (tx *DB) {
 	tx = db.getInstance()
 	tx.Statement.DeleteEdge(edgeTypeName, edge)
+	tx.addHookModel(hookEventDelete, edge)
 	return
 }
 
@@ -217,10 +225,8 @@ func (db *DB) Debug() (tx *DB) {
 	tx = db.getInstance()
 	confNew := *tx.conf
 	confNew.logger = tx.conf.logger.LogMode(logger.DebugLevel)
-	return &DB{
-		Statement:   tx.Statement,
-		conf:        &confNew,
-		sessionPool: tx.sessionPool,
-		clone:       1,
-	}
+	debugTx := *tx
+	debugTx.conf = &confNew
+	debugTx.clone = 1
+	return &debugTx
 }
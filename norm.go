@@ -1,6 +1,7 @@
 package norm
 
 import (
+	"context"
 	"fmt"
 	"github.com/haysons/norm/logger"
 	"github.com/haysons/norm/resolver"
@@ -8,11 +9,12 @@ import (
 	nebula "github.com/vesoft-inc/nebula-go/v3"
 	"net"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // DB uses statement.Statement to construct nGQL statements,
-// and then executes them through nebula.SessionPool.
+// and then executes them through the Executor, which is nebula.SessionPool by default.
 // You can retrieve the results via methods such as Find, Exec, or Pluck.
 // DB is concurrency-safe: multiple statements can be executed concurrently using a single DB instance.
 //
@@ -24,10 +26,21 @@ import (
 //   - Embedded fields in struct definitions are NOT supported.
 //     Avoid using embedded fields when defining vertex/edge structs.
 type DB struct {
-	Statement   *statement.Statement
-	conf        *Config
-	sessionPool *nebula.SessionPool
-	clone       int
+	Statement  *statement.Statement
+	conf       *Config
+	executor   Executor
+	replicas   *replicaSet
+	inUse      *int64
+	clone      int
+	ctx        context.Context
+	hookModels []hookModel
+	retry      *bool
+	target     routeTarget
+	space      string
+	dryRun     bool
+	force      bool // execute the statement even in dry-run mode, eg: Ping and HealthCheck
+	pinned     Executor
+	chain      *pluginChain
 }
 
 // Open creates a new DB instance.
@@ -36,6 +49,49 @@ type DB struct {
 // parses the server address, and creates the session pool.
 // The returned DB instance is ready to execute nGQL statements.
 func Open(conf *Config, opts ...ConfigOption) (*DB, error) {
+	if err := initConfig(conf, opts...); err != nil {
+		return nil, err
+	}
+	pool, err := newSessionPool(conf, conf.Addresses, conf.Username, conf.Password)
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := newReplicaSet(conf)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return newDB(conf, pool, replicas), nil
+}
+
+// OpenWithExecutor creates a new DB instance which executes statements through the given executor instead of
+// the session pool created from Config, eg: a fake for testing, or a router across multiple clusters.
+// Addresses, Replicas and the options of the session pool in Config are not used.
+func OpenWithExecutor(conf *Config, executor Executor, opts ...ConfigOption) (*DB, error) {
+	if executor == nil {
+		return nil, fmt.Errorf("norm: %w, executor should not be nil", ErrInvalidValue)
+	}
+	if err := initConfig(conf, opts...); err != nil {
+		return nil, err
+	}
+	return newDB(conf, executor, nil), nil
+}
+
+func newDB(conf *Config, executor Executor, replicas *replicaSet) *DB {
+	return &DB{
+		Statement: statement.New(),
+		conf:      conf,
+		executor:  executor,
+		replicas:  replicas,
+		inUse:     new(int64),
+		chain:     new(pluginChain),
+		clone:     1, // when clone is 1, the Statement object will be copied to ensure that the same singleton build statement does not affect each other.
+		ctx:       context.Background(),
+	}
+}
+
+// initConfig applies the options and initializes the timezone and the logger
+func initConfig(conf *Config, opts ...ConfigOption) error {
 	for _, o := range opts {
 		o.apply(conf)
 	}
@@ -43,7 +99,7 @@ func Open(conf *Config, opts ...ConfigOption) (*DB, error) {
 	if conf.TimezoneName != "" {
 		loc, err := time.LoadLocation(conf.TimezoneName)
 		if err != nil {
-			return nil, fmt.Errorf("norm: load timezone failed: %v", err)
+			return fmt.Errorf("norm: load timezone failed: %v", err)
 		}
 		conf.timezone = loc
 	} else {
@@ -54,12 +110,16 @@ func Open(conf *Config, opts ...ConfigOption) (*DB, error) {
 	if conf.logger == nil {
 		conf.logger = logger.Default
 	}
+	return nil
+}
 
-	hostAddr, err := parseServerAddr(conf.Addresses)
+// newSessionPool creates the session pool connected to the servers of the given addresses
+func newSessionPool(conf *Config, addresses []string, username, password string) (*nebula.SessionPool, error) {
+	hostAddr, err := parseServerAddr(addresses)
 	if err != nil {
 		return nil, err
 	}
-	poolConf, err := nebula.NewSessionPoolConf(conf.Username, conf.Password, hostAddr, conf.SpaceName, parseSessionOptions(conf)...)
+	poolConf, err := nebula.NewSessionPoolConf(username, password, hostAddr, conf.SpaceName, parseSessionOptions(conf)...)
 	if err != nil {
 		return nil, fmt.Errorf("norm: build session pool conf failed: %v", err)
 	}
@@ -67,14 +127,7 @@ func Open(conf *Config, opts ...ConfigOption) (*DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("norm: create session pool failed: %v", err)
 	}
-
-	db := &DB{
-		Statement:   statement.New(),
-		conf:        conf,
-		sessionPool: pool,
-		clone:       1, // when clone is 1, the Statement object will be copied to ensure that the same singleton build statement does not affect each other.
-	}
-	return db, nil
+	return pool, nil
 }
 
 func parseServerAddr(addrList []string) ([]nebula.HostAddress, error) {
@@ -116,14 +169,88 @@ func parseSessionOptions(conf *Config) []nebula.SessionPoolConfOption {
 
 func (db *DB) getInstance() *DB {
 	if db.clone > 0 {
-		tx := &DB{conf: db.conf, sessionPool: db.sessionPool, clone: 0}
+		tx := &DB{conf: db.conf, executor: db.executor, replicas: db.replicas, inUse: db.inUse, chain: db.chain, clone: 0, ctx: db.ctx, space: db.space, dryRun: db.dryRun, target: db.target}
 		tx.Statement = statement.New()
+		if db.conf.ParameterizedQuery {
+			tx.Statement.Parameterize()
+		}
 		return tx
 	}
 	return db
 }
 
+// WithContext returns a DB that executes statements with the given context. The context flows into
+// statement execution, logging and hooks; once it is cancelled or its deadline is exceeded, the
+// in-flight statement is abandoned and ctx.Err() is returned.
+//
+//	err := db.WithContext(ctx).Fetch("player", "player1001").Yield("vertex as v").FindCol("v", player)
+func (db *DB) WithContext(ctx context.Context) *DB {
+	tx := *db.getInstance()
+	tx.clone = db.clone // keep the statement being built when called in the middle of a chain
+	tx.ctx = ctx
+	return &tx
+}
+
+// Space returns a DB that executes statements in the given graph space instead of the SpaceName of Config.
+// Each statement is prefixed with 'USE space;', and the session is switched back to the default space by the
+// session pool after execution, so the space is never leaked to other statements and the returned DB is safe
+// for concurrent use. the statements fail with ErrInvalidValue if the name contains a backtick or semicolon.
+//
+//	err := db.Space("tenant_42").Fetch("player", "player1001").Yield("vertex as v").FindCol("v", player)
+func (db *DB) Space(name string) *DB {
+	tx := *db.getInstance()
+	tx.clone = db.clone // keep the statement being built when called in the middle of a chain
+	tx.space = name
+	return &tx
+}
+
+// DryRun returns a DB whose statements are built, logged and passed through the plugins, but not executed,
+// an empty result is returned instead. so Find assigns nothing, Take returns ErrRecordNotFound, Migrator
+// considers that no schema exists, and the after hooks such as AfterInsert are not called.
+//
+//	err := db.DryRun().Migrator().AutoMigrateVertexes(Player{})
+func (db *DB) DryRun() *DB {
+	tx := *db.getInstance()
+	tx.clone = db.clone // keep the statement being built when called in the middle of a chain
+	tx.dryRun = true
+	return &tx
+}
+
+// isDryRun reports whether the statements are built but not executed, by DryRun or Config.DryRun
+func (db *DB) isDryRun() bool {
+	return db.dryRun || db.conf.DryRun
+}
+
+// spaceName returns the graph space in which the statements of the current DB are executed
+func (db *DB) spaceName() string {
+	if db.space != "" {
+		return db.space
+	}
+	return db.conf.SpaceName
+}
+
+// useSpace prefixes nGQL with the statement switching to the space of the current DB, the space name is rejected
+// if it contains a backtick or semicolon, since it is often derived from request data
+func (db *DB) useSpace(nGQL string) (string, error) {
+	if db.space == "" {
+		return nGQL, nil
+	}
+	if strings.ContainsAny(db.space, "`;") {
+		return "", fmt.Errorf("norm: %w, invalid space name: %q", ErrInvalidValue, db.space)
+	}
+	return "USE `" + db.space + "`; " + nGQL, nil
+}
+
+// Context returns the context used by the current DB
+func (db *DB) Context() context.Context {
+	if db.ctx == nil {
+		return context.Background()
+	}
+	return db.ctx
+}
+
 func (db *DB) Close() error {
-	db.sessionPool.Close()
+	db.executor.Close()
+	db.replicas.close()
 	return nil
 }
@@ -0,0 +1,130 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSpace(t *testing.T) {
+	db := &DB{conf: &Config{}, clone: 1}
+	nGQL := `FETCH PROP ON player "player100" YIELD vertex AS v;`
+	useNGQL, err := db.useSpace(nGQL)
+	assert.NoError(t, err)
+	assert.Equal(t, nGQL, useNGQL)
+
+	tenant := db.Space("tenant_42")
+	assert.Equal(t, "", db.space)
+	useNGQL, err = tenant.useSpace(nGQL)
+	assert.NoError(t, err)
+	assert.Equal(t, "USE `tenant_42`; "+nGQL, useNGQL)
+	// the space is kept by the sessions created from the DB
+	assert.Equal(t, "tenant_42", tenant.Fetch("player", "player100").space)
+	assert.Equal(t, "tenant_43", tenant.Fetch("player", "player100").Space("tenant_43").space)
+	assert.Equal(t, "", db.Fetch("player", "player100").space)
+}
+
+func TestSpaceInvalidName(t *testing.T) {
+	executor := &fakeExecutor{}
+	db, err := OpenWithExecutor(&Config{}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+
+	// the names which are able to break out of the USE statement are rejected before execution
+	for _, name := range []string{"a` ; DROP SPACE x; USE `b", "a;b", "a`b"} {
+		err = db.Space(name).Raw("YIELD 1").Exec()
+		assert.True(t, errors.Is(err, ErrInvalidValue))
+	}
+	assert.Empty(t, executor.stmts)
+}
+
+func TestTraceRecord(t *testing.T) {
+	query := &Query{NGQL: `LOOKUP ON player YIELD id(vertex) AS vid, properties(vertex).name AS name;`, Space: "basketballplayer"}
+	begin := time.Now().Add(-time.Second)
+	record := traceRecord(query, newPlayersResult(t, "kobe", "james"), nil, begin)
+	assert.Equal(t, query.NGQL, record.NGQL)
+	assert.Equal(t, begin, record.Begin)
+	assert.True(t, record.Elapsed >= time.Second)
+	assert.Equal(t, 2, record.Rows)
+	assert.Equal(t, "basketballplayer", record.Space)
+	assert.Equal(t, "LOOKUP", record.Kind)
+
+	record = traceRecord(query, nil, errors.New("failed"), begin)
+	assert.Equal(t, 0, record.Rows)
+	assert.EqualError(t, record.Err, "failed")
+
+	db := &DB{conf: &Config{SpaceName: "basketballplayer"}, clone: 1}
+	assert.Equal(t, "basketballplayer", db.spaceName())
+	assert.Equal(t, "tenant_42", db.Space("tenant_42").spaceName())
+}
+
+func TestDryRun(t *testing.T) {
+	var nGQLs []string
+	db := &DB{conf: &Config{logger: logger.Default.LogMode(logger.SilentLevel)}, clone: 1}
+	assert.NoError(t, db.Use(capturePlugin{nGQLs: &nGQLs}))
+
+	dryRun := db.DryRun()
+	inserted := &hookPlayer{VID: "player100", Name: "kobe"}
+	assert.NoError(t, dryRun.InsertVertex(inserted).Exec())
+	// the after hooks are not called as nothing is written
+	assert.False(t, inserted.inserted)
+	player := new(hookPlayer)
+	assert.ErrorIs(t, dryRun.Fetch("player", "player100").Yield("vertex AS v").TakeCol("v", player), ErrRecordNotFound)
+	assert.NoError(t, dryRun.Migrator().AutoMigrateVertexes(hookPlayer{}))
+	assert.Equal(t, []string{
+		`INSERT VERTEX player(name) VALUES "player100":("kobe");`,
+		`FETCH PROP ON player "player100" YIELD vertex AS v | LIMIT 1;`,
+		`SHOW TAGS`,
+		`CREATE TAG IF NOT EXISTS player(name string);`,
+	}, nGQLs)
+
+	nGQLs = nil
+	db.conf.DryRun = true
+	players := make([]*hookPlayer, 0)
+	assert.NoError(t, db.Lookup("player").Yield("vertex AS v").FindCol("v", &players))
+	assert.Empty(t, players)
+	inserted = &hookPlayer{Name: "tim"}
+	assert.NoError(t, db.InsertVertex(inserted).Exec())
+	assert.Equal(t, "player_tim", inserted.VID)
+	assert.False(t, inserted.inserted)
+	assert.Equal(t, []string{`LOOKUP ON player YIELD vertex AS v;`, `INSERT VERTEX player(name) VALUES "player_tim":("tim");`}, nGQLs)
+}
+
+func TestWithContextCancel(t *testing.T) {
+	var executed int32
+	res := newResult(t, []string{"v"})
+	release := make(chan struct{})
+	defer close(release)
+	// the executor blocks until the test ends, as nebula.SessionPool is not aware of the context
+	executor := funcExecutor(func(stmt string) (*nebula.ResultSet, error) {
+		atomic.AddInt32(&executed, 1)
+		<-release
+		return res, nil
+	})
+	db, err := OpenWithExecutor(&Config{}, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	begin := time.Now()
+	err = db.WithContext(ctx).Go().From("player100").Over("follow").Yield("dst(edge) AS id").Exec()
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(begin), time.Second)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	players := make([]string, 0)
+	err = db.WithContext(ctx).Lookup("player").Yield("id(vertex) AS v").FindCol("v", &players)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&executed))
+
+	// the statement is not executed if the context is already done
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, db.WithContext(ctx).Lookup("player").Yield("id(vertex) AS v").Exec(), context.Canceled)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&executed))
+}
@@ -0,0 +1,416 @@
+package normtest
+
+import (
+	"encoding/json"
+	"fmt"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+// Fixture is an executed statement along with its result, which is saved by Recorder and loaded by Replayer.
+// the values of the result are saved in a readable form, so that the fixture files can be reviewed and edited.
+type Fixture struct {
+	// NGQL the executed statement
+	NGQL string `json:"ngql"`
+
+	// Params the parameters of the statement
+	Params json.RawMessage `json:"params,omitempty"`
+
+	// ErrorCode the error code of the result
+	ErrorCode nebula.ErrorCode `json:"error_code,omitempty"`
+
+	// ErrorMsg the error message of the result
+	ErrorMsg string `json:"error_msg,omitempty"`
+
+	// SpaceName the space name of the result
+	SpaceName string `json:"space_name,omitempty"`
+
+	// Columns the column names of the result, nil if the result has no data set
+	Columns []string `json:"columns,omitempty"`
+
+	// Rows the rows of the result
+	Rows [][]*FixtureValue `json:"rows,omitempty"`
+
+	// Err the error returned by the executor instead of a result, eg: the connection is broken
+	Err string `json:"err,omitempty"`
+}
+
+// FixtureValue is the readable form of a nebula value, only one of the fields is set
+type FixtureValue struct {
+	Null     *nebulaType.NullType      `json:"null,omitempty"`
+	Bool     *bool                     `json:"bool,omitempty"`
+	Int      *int64                    `json:"int,omitempty"`
+	Float    *float64                  `json:"float,omitempty"`
+	String   *string                   `json:"string,omitempty"`
+	Date     *nebulaType.Date          `json:"date,omitempty"`
+	Time     *nebulaType.Time          `json:"time,omitempty"`
+	DateTime *nebulaType.DateTime      `json:"datetime,omitempty"`
+	Duration *nebulaType.Duration      `json:"duration,omitempty"`
+	Vertex   *FixtureVertex            `json:"vertex,omitempty"`
+	Edge     *FixtureEdge              `json:"edge,omitempty"`
+	Path     *FixturePath              `json:"path,omitempty"`
+	List     *[]*FixtureValue          `json:"list,omitempty"`
+	Set      *[]*FixtureValue          `json:"set,omitempty"`
+	Map      *map[string]*FixtureValue `json:"map,omitempty"`
+}
+
+// FixtureVertex is the readable form of a vertex
+type FixtureVertex struct {
+	VID  *FixtureValue `json:"vid"`
+	Tags []*FixtureTag `json:"tags,omitempty"`
+}
+
+// FixtureTag is the readable form of a tag of the vertex
+type FixtureTag struct {
+	Name  string                   `json:"name"`
+	Props map[string]*FixtureValue `json:"props,omitempty"`
+}
+
+// FixtureEdge is the readable form of an edge, the type is negative if the edge is returned in reverse direction
+type FixtureEdge struct {
+	Src   *FixtureValue            `json:"src"`
+	Dst   *FixtureValue            `json:"dst"`
+	Type  int32                    `json:"type"`
+	Name  string                   `json:"name"`
+	Rank  int64                    `json:"rank,omitempty"`
+	Props map[string]*FixtureValue `json:"props,omitempty"`
+}
+
+// FixturePath is the readable form of a path
+type FixturePath struct {
+	Src   *FixtureVertex `json:"src"`
+	Steps []*FixtureStep `json:"steps,omitempty"`
+}
+
+// FixtureStep is the readable form of a step of the path
+type FixtureStep struct {
+	Dst   *FixtureVertex           `json:"dst"`
+	Type  int32                    `json:"type"`
+	Name  string                   `json:"name"`
+	Rank  int64                    `json:"rank,omitempty"`
+	Props map[string]*FixtureValue `json:"props,omitempty"`
+}
+
+// newFixture records the result of the statement
+func newFixture(stmt string, params map[string]any, res *nebula.ResultSet, err error) (*Fixture, error) {
+	fixture := &Fixture{NGQL: stmt, Params: marshalParams(params)}
+	if err != nil {
+		fixture.Err = err.Error()
+		return fixture, nil
+	}
+	if res == nil {
+		return fixture, nil
+	}
+	fixture.ErrorCode = res.GetErrorCode()
+	fixture.ErrorMsg = res.GetErrorMsg()
+	fixture.SpaceName = res.GetSpaceName()
+	if !res.IsSetData() {
+		return fixture, nil
+	}
+	fixture.Columns = res.GetColNames()
+	if fixture.Columns == nil {
+		fixture.Columns = make([]string, 0)
+	}
+	for _, row := range res.GetRows() {
+		values, err := encodeValues(row.GetValues())
+		if err != nil {
+			return nil, fmt.Errorf("normtest: record result of %s failed: %v", stmt, err)
+		}
+		fixture.Rows = append(fixture.Rows, values)
+	}
+	return fixture, nil
+}
+
+// resultSet rebuilds the result of the fixture
+func (f *Fixture) resultSet() (*nebula.ResultSet, error) {
+	resp := &graph.ExecutionResponse{
+		ErrorCode: nebulaType.ErrorCode(f.ErrorCode),
+		ErrorMsg:  []byte(f.ErrorMsg),
+		SpaceName: []byte(f.SpaceName),
+	}
+	if f.Columns != nil {
+		resp.Data = &nebulaType.DataSet{
+			ColumnNames: make([][]byte, 0, len(f.Columns)),
+			Rows:        make([]*nebulaType.Row, 0, len(f.Rows)),
+		}
+		for _, col := range f.Columns {
+			resp.Data.ColumnNames = append(resp.Data.ColumnNames, []byte(col))
+		}
+		for _, row := range f.Rows {
+			values, err := decodeValues(row)
+			if err != nil {
+				return nil, fmt.Errorf("normtest: replay result of %s failed: %v", f.NGQL, err)
+			}
+			resp.Data.Rows = append(resp.Data.Rows, &nebulaType.Row{Values: values})
+		}
+	}
+	return nebula.GenResultSet(resp)
+}
+
+func encodeValue(v *nebulaType.Value) (*FixtureValue, error) {
+	fv := new(FixtureValue)
+	switch {
+	case v == nil:
+		null := nebulaType.NullType___NULL__
+		fv.Null = &null
+	case v.IsSetNVal():
+		fv.Null = v.NVal
+	case v.IsSetBVal():
+		fv.Bool = v.BVal
+	case v.IsSetIVal():
+		fv.Int = v.IVal
+	case v.IsSetFVal():
+		fv.Float = v.FVal
+	case v.IsSetSVal():
+		s := string(v.SVal)
+		fv.String = &s
+	case v.IsSetDVal():
+		fv.Date = v.DVal
+	case v.IsSetTVal():
+		fv.Time = v.TVal
+	case v.IsSetDtVal():
+		fv.DateTime = v.DtVal
+	case v.IsSetDuVal():
+		fv.Duration = v.DuVal
+	case v.IsSetVVal():
+		vertex, err := encodeVertex(v.VVal)
+		if err != nil {
+			return nil, err
+		}
+		fv.Vertex = vertex
+	case v.IsSetEVal():
+		e := v.EVal
+		src, err := encodeValue(e.Src)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := encodeValue(e.Dst)
+		if err != nil {
+			return nil, err
+		}
+		props, err := encodeProps(e.Props)
+		if err != nil {
+			return nil, err
+		}
+		fv.Edge = &FixtureEdge{Src: src, Dst: dst, Type: int32(e.Type), Name: string(e.Name), Rank: int64(e.Ranking), Props: props}
+	case v.IsSetPVal():
+		src, err := encodeVertex(v.PVal.Src)
+		if err != nil {
+			return nil, err
+		}
+		path := &FixturePath{Src: src}
+		for _, step := range v.PVal.Steps {
+			dst, err := encodeVertex(step.Dst)
+			if err != nil {
+				return nil, err
+			}
+			props, err := encodeProps(step.Props)
+			if err != nil {
+				return nil, err
+			}
+			path.Steps = append(path.Steps, &FixtureStep{Dst: dst, Type: int32(step.Type), Name: string(step.Name), Rank: int64(step.Ranking), Props: props})
+		}
+		fv.Path = path
+	case v.IsSetLVal():
+		list, err := encodeValues(v.LVal.Values)
+		if err != nil {
+			return nil, err
+		}
+		fv.List = &list
+	case v.IsSetUVal():
+		set, err := encodeValues(v.UVal.Values)
+		if err != nil {
+			return nil, err
+		}
+		fv.Set = &set
+	case v.IsSetMVal():
+		m, err := encodeProps(v.MVal.Kvs)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			m = make(map[string]*FixtureValue)
+		}
+		fv.Map = &m
+	default:
+		return nil, fmt.Errorf("unsupported nebula value %s", v)
+	}
+	return fv, nil
+}
+
+func encodeValues(values []*nebulaType.Value) ([]*FixtureValue, error) {
+	fvs := make([]*FixtureValue, 0, len(values))
+	for _, v := range values {
+		fv, err := encodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		fvs = append(fvs, fv)
+	}
+	return fvs, nil
+}
+
+func encodeProps(props map[string]*nebulaType.Value) (map[string]*FixtureValue, error) {
+	if len(props) == 0 {
+		return nil, nil
+	}
+	fvs := make(map[string]*FixtureValue, len(props))
+	for name, v := range props {
+		fv, err := encodeValue(v)
+		if err != nil {
+			return nil, err
+		}
+		fvs[name] = fv
+	}
+	return fvs, nil
+}
+
+func encodeVertex(v *nebulaType.Vertex) (*FixtureVertex, error) {
+	if v == nil {
+		return nil, nil
+	}
+	vid, err := encodeValue(v.Vid)
+	if err != nil {
+		return nil, err
+	}
+	vertex := &FixtureVertex{VID: vid}
+	for _, tag := range v.Tags {
+		props, err := encodeProps(tag.Props)
+		if err != nil {
+			return nil, err
+		}
+		vertex.Tags = append(vertex.Tags, &FixtureTag{Name: string(tag.Name), Props: props})
+	}
+	return vertex, nil
+}
+
+func decodeValue(fv *FixtureValue) (*nebulaType.Value, error) {
+	v := new(nebulaType.Value)
+	switch {
+	case fv == nil:
+		null := nebulaType.NullType___NULL__
+		v.NVal = &null
+	case fv.Null != nil:
+		v.NVal = fv.Null
+	case fv.Bool != nil:
+		v.BVal = fv.Bool
+	case fv.Int != nil:
+		v.IVal = fv.Int
+	case fv.Float != nil:
+		v.FVal = fv.Float
+	case fv.String != nil:
+		v.SVal = []byte(*fv.String)
+	case fv.Date != nil:
+		v.DVal = fv.Date
+	case fv.Time != nil:
+		v.TVal = fv.Time
+	case fv.DateTime != nil:
+		v.DtVal = fv.DateTime
+	case fv.Duration != nil:
+		v.DuVal = fv.Duration
+	case fv.Vertex != nil:
+		vertex, err := decodeVertex(fv.Vertex)
+		if err != nil {
+			return nil, err
+		}
+		v.VVal = vertex
+	case fv.Edge != nil:
+		e := fv.Edge
+		src, err := decodeValue(e.Src)
+		if err != nil {
+			return nil, err
+		}
+		dst, err := decodeValue(e.Dst)
+		if err != nil {
+			return nil, err
+		}
+		props, err := decodeProps(e.Props)
+		if err != nil {
+			return nil, err
+		}
+		v.EVal = &nebulaType.Edge{Src: src, Dst: dst, Type: nebulaType.EdgeType(e.Type), Name: []byte(e.Name), Ranking: nebulaType.EdgeRanking(e.Rank), Props: props}
+	case fv.Path != nil:
+		src, err := decodeVertex(fv.Path.Src)
+		if err != nil {
+			return nil, err
+		}
+		path := &nebulaType.Path{Src: src}
+		for _, step := range fv.Path.Steps {
+			dst, err := decodeVertex(step.Dst)
+			if err != nil {
+				return nil, err
+			}
+			props, err := decodeProps(step.Props)
+			if err != nil {
+				return nil, err
+			}
+			path.Steps = append(path.Steps, &nebulaType.Step{Dst: dst, Type: nebulaType.EdgeType(step.Type), Name: []byte(step.Name), Ranking: nebulaType.EdgeRanking(step.Rank), Props: props})
+		}
+		v.PVal = path
+	case fv.List != nil:
+		list, err := decodeValues(*fv.List)
+		if err != nil {
+			return nil, err
+		}
+		v.LVal = &nebulaType.NList{Values: list}
+	case fv.Set != nil:
+		set, err := decodeValues(*fv.Set)
+		if err != nil {
+			return nil, err
+		}
+		v.UVal = &nebulaType.NSet{Values: set}
+	case fv.Map != nil:
+		m, err := decodeProps(*fv.Map)
+		if err != nil {
+			return nil, err
+		}
+		v.MVal = &nebulaType.NMap{Kvs: m}
+	default:
+		return nil, fmt.Errorf("empty fixture value")
+	}
+	return v, nil
+}
+
+func decodeValues(fvs []*FixtureValue) ([]*nebulaType.Value, error) {
+	values := make([]*nebulaType.Value, 0, len(fvs))
+	for _, fv := range fvs {
+		v, err := decodeValue(fv)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func decodeProps(fvs map[string]*FixtureValue) (map[string]*nebulaType.Value, error) {
+	props := make(map[string]*nebulaType.Value, len(fvs))
+	for name, fv := range fvs {
+		v, err := decodeValue(fv)
+		if err != nil {
+			return nil, err
+		}
+		props[name] = v
+	}
+	return props, nil
+}
+
+func decodeVertex(fv *FixtureVertex) (*nebulaType.Vertex, error) {
+	if fv == nil {
+		return nil, nil
+	}
+	vid, err := decodeValue(fv.VID)
+	if err != nil {
+		return nil, err
+	}
+	vertex := &nebulaType.Vertex{Vid: vid}
+	for _, tag := range fv.Tags {
+		props, err := decodeProps(tag.Props)
+		if err != nil {
+			return nil, err
+		}
+		vertex.Tags = append(vertex.Tags, &nebulaType.Tag{Name: []byte(tag.Name), Props: props})
+	}
+	return vertex, nil
+}
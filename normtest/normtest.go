@@ -0,0 +1,242 @@
+// Package normtest provides a mock executor, so that the code built on norm can be unit tested without nebula graph.
+// The expected statements are declared in advance along with their results, the mock matches the executed
+// statements against them in order, and the test fails if some expectations are not met.
+//
+//	db, mock := normtest.New(t)
+//	mock.ExpectQuery(`^LOOKUP ON player`).
+//		WillReturnRows([]string{"v"}, []any{normtest.Vertex{VID: "player100", Tags: []normtest.Tag{{Name: "player", Props: map[string]any{"name": "Tim Duncan"}}}}})
+//	mock.ExpectExec(`^DELETE VERTEX`).WillReturnError(nebula.ErrorCode_E_EXECUTION_ERROR, "storage error")
+//
+//	players := make([]*Player, 0)
+//	err := db.Lookup("player").Yield("vertex AS v").FindCol("v", &players)
+//
+// Recorder and Replayer are executors to record the statements executed against nebula graph along with their
+// results into a fixture file, and to replay them later, so that the real query code paths can be tested offline.
+package normtest
+
+import (
+	"fmt"
+	"github.com/haysons/norm"
+	"github.com/haysons/norm/logger"
+	"github.com/haysons/norm/statement"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// TB is the subset of testing.TB used by the mock
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+	Cleanup(func())
+}
+
+// New creates a DB which executes statements through a new Mock, it is the same as Open(t, &norm.Config{})
+func New(t TB) (*norm.DB, *Mock) {
+	t.Helper()
+	return Open(t, &norm.Config{})
+}
+
+// Open creates a DB with the config which executes statements through a new Mock, eg: to test the parameterized
+// queries. logging is disabled unless it is overridden by opts.
+func Open(t TB, conf *norm.Config, opts ...norm.ConfigOption) (*norm.DB, *Mock) {
+	t.Helper()
+	mock := NewMock(t)
+	opts = append([]norm.ConfigOption{norm.WithLogger(logger.Default.LogMode(logger.SilentLevel))}, opts...)
+	db, err := norm.OpenWithExecutor(conf, mock, opts...)
+	if err != nil {
+		t.Errorf("normtest: open db failed: %v", err)
+	}
+	return db, mock
+}
+
+// NewMock creates a mock executor, which can be passed to norm.OpenWithExecutor. the test fails on cleanup if some
+// expectations are not met.
+func NewMock(t TB) *Mock {
+	m := &Mock{t: t, ordered: true}
+	t.Cleanup(func() {
+		if err := m.ExpectationsWereMet(); err != nil {
+			t.Errorf("%v", err)
+		}
+	})
+	return m
+}
+
+// Mock is an implementation of norm.Executor, which returns the results of the expectations instead of
+// executing the statements. Mock is safe for concurrent use.
+type Mock struct {
+	t            TB
+	mu           sync.Mutex
+	ordered      bool
+	expectations []*Expectation
+	closed       bool
+}
+
+var _ norm.Executor = (*Mock)(nil)
+
+// MatchExpectationsInOrder sets whether the statements should be executed in the order of the expectations,
+// default is true. when disabled, a statement matches the first unmet expectation that accepts it.
+func (m *Mock) MatchExpectationsInOrder(ordered bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ordered = ordered
+}
+
+// ExpectQuery expects a read statement matching the regular expression, eg: GO, FETCH, LOOKUP, MATCH.
+// Note that nGQL contains many characters special to regular expressions, use regexp.QuoteMeta to match them literally.
+func (m *Mock) ExpectQuery(pattern string) *Expectation {
+	return m.expect(kindQuery, pattern)
+}
+
+// ExpectExec expects a write statement matching the regular expression, eg: INSERT, UPDATE, DELETE, CREATE
+func (m *Mock) ExpectExec(pattern string) *Expectation {
+	return m.expect(kindExec, pattern)
+}
+
+func (m *Mock) expect(kind, pattern string) *Expectation {
+	m.t.Helper()
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		m.t.Errorf("normtest: invalid pattern of %s: %v", kind, err)
+		re = regexp.MustCompile(regexp.QuoteMeta(pattern))
+	}
+	e := &Expectation{t: m.t, kind: kind, re: re, resp: &graph.ExecutionResponse{}}
+	m.mu.Lock()
+	m.expectations = append(m.expectations, e)
+	m.mu.Unlock()
+	return e
+}
+
+// ExpectationsWereMet returns an error if some expectations were not triggered
+func (m *Mock) ExpectationsWereMet() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	unmet := make([]string, 0)
+	for _, e := range m.expectations {
+		if !e.triggered {
+			unmet = append(unmet, e.String())
+		}
+	}
+	if len(unmet) == 0 {
+		return nil
+	}
+	return fmt.Errorf("normtest: %d expectations were not met:\n\t%s", len(unmet), strings.Join(unmet, "\n\t"))
+}
+
+// Execute returns the result of the expectation matching the statement
+func (m *Mock) Execute(stmt string) (*nebula.ResultSet, error) {
+	return m.ExecuteWithParameter(stmt, nil)
+}
+
+// ExecuteWithParameter returns the result of the expectation matching the statement and the parameters,
+// an error is returned if no expectation matches it
+func (m *Mock) ExecuteWithParameter(stmt string, params map[string]any) (*nebula.ResultSet, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return nil, fmt.Errorf("normtest: executor is closed, statement: %s", stmt)
+	}
+	kind := kindExec
+	if statement.IsReadOnly(stmt) {
+		kind = kindQuery
+	}
+	for _, e := range m.expectations {
+		if e.triggered {
+			continue
+		}
+		err := e.match(kind, stmt, params)
+		if err == nil {
+			e.triggered = true
+			if e.err != nil {
+				return nil, e.err
+			}
+			return nebula.GenResultSet(e.resp)
+		}
+		if m.ordered {
+			return nil, fmt.Errorf("normtest: %v, next expectation is %s", err, e)
+		}
+	}
+	return nil, fmt.Errorf("normtest: unexpected %s, params: %v, statement: %s", kind, params, stmt)
+}
+
+// Close marks the mock as closed, the statements executed after it fail
+func (m *Mock) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+}
+
+const (
+	kindQuery = "query"
+	kindExec  = "exec"
+)
+
+// Expectation is an expected statement along with its result, it is triggered at most once
+type Expectation struct {
+	t         TB
+	kind      string
+	re        *regexp.Regexp
+	params    map[string]any
+	resp      *graph.ExecutionResponse
+	err       error
+	triggered bool
+}
+
+// WithParams expects the statement to be executed with the parameters, they are compared by reflect.DeepEqual
+// with the parameters formatted by norm, eg: integers are int64.
+func (e *Expectation) WithParams(params map[string]any) *Expectation {
+	e.params = params
+	return e
+}
+
+// WillReturnRows sets the result of the statement, each row should have a value for each column, refer to Value
+// for the supported values.
+func (e *Expectation) WillReturnRows(cols []string, rows ...[]any) *Expectation {
+	e.t.Helper()
+	ds, err := dataSet(cols, rows)
+	if err != nil {
+		e.t.Errorf("%v", err)
+		return e
+	}
+	e.resp.Data = ds
+	return e
+}
+
+// WillReturnError sets the result of the statement to a failed one with the error code, which is returned by norm
+// as *norm.Error
+func (e *Expectation) WillReturnError(code nebula.ErrorCode, msg ...string) *Expectation {
+	e.resp.ErrorCode = nebulaType.ErrorCode(code)
+	e.resp.ErrorMsg = []byte(strings.Join(msg, " "))
+	return e
+}
+
+// WillFail makes the executor return the error instead of a result, eg: the connection to nebula graph is broken
+func (e *Expectation) WillFail(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// match returns an error describing the mismatch, nil if the statement matches the expectation
+func (e *Expectation) match(kind, stmt string, params map[string]any) error {
+	if kind != e.kind {
+		return fmt.Errorf("%s was executed but %s was expected, statement: %s", kind, e.kind, stmt)
+	}
+	if !e.re.MatchString(stmt) {
+		return fmt.Errorf("statement does not match %q, statement: %s", e.re, stmt)
+	}
+	if e.params != nil && !reflect.DeepEqual(e.params, params) {
+		return fmt.Errorf("params %v do not match %v, statement: %s", params, e.params, stmt)
+	}
+	return nil
+}
+
+func (e *Expectation) String() string {
+	if e.params != nil {
+		return fmt.Sprintf("%s matching %q with params %v", e.kind, e.re, e.params)
+	}
+	return fmt.Sprintf("%s matching %q", e.kind, e.re)
+}
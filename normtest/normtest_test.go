@@ -0,0 +1,148 @@
+package normtest
+
+import (
+	"errors"
+	"fmt"
+	"github.com/haysons/norm"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"regexp"
+	"testing"
+	"time"
+)
+
+type player struct {
+	VID  string `norm:"vertex_id"`
+	Name string `norm:"prop:name"`
+	Age  int    `norm:"prop:age"`
+}
+
+func (p player) VertexID() string {
+	return p.VID
+}
+
+func (p player) VertexTagName() string {
+	return "player"
+}
+
+type serve struct {
+	SrcID     string `norm:"edge_src_id"`
+	DstID     string `norm:"edge_dst_id"`
+	Rank      int    `norm:"edge_rank"`
+	StartYear int64  `norm:"prop:start_year"`
+}
+
+func (s serve) EdgeTypeName() string {
+	return "serve"
+}
+
+type serveRecord struct {
+	Player  *player           `norm:"col:v"`
+	Serve   *serve            `norm:"col:e"`
+	Teams   []string          `norm:"col:teams"`
+	Scores  []int             `norm:"col:scores"`
+	Attrs   map[string]string `norm:"col:attrs"`
+	Updated time.Time         `norm:"col:updated"`
+	Deleted *string           `norm:"col:deleted"`
+}
+
+func TestMock(t *testing.T) {
+	db, mock := Open(t, &norm.Config{ParameterizedQuery: true})
+	updated := time.Date(2024, 8, 20, 11, 16, 30, 0, time.UTC)
+	mock.ExpectQuery(`^GO FROM "player100" OVER serve`).WillReturnRows(
+		[]string{"v", "e", "teams", "scores", "attrs", "updated", "deleted"},
+		[]any{
+			Vertex{VID: "player100", Tags: []Tag{{Name: "player", Props: map[string]any{
+				"name": "Tim Duncan",
+				"age":  42,
+			}}}},
+			Edge{Src: "player100", Dst: "team204", Name: "serve", Rank: 1, Props: map[string]any{"start_year": 1997}},
+			[]any{"spurs"},
+			Set{98, 99},
+			map[string]any{"position": "PF"},
+			updated,
+			nil,
+		},
+	)
+	mock.ExpectExec(`^DELETE VERTEX "player100"`).WillReturnError(nebula.ErrorCode_E_EXECUTION_ERROR, "storage error")
+	mock.ExpectQuery(regexp.QuoteMeta("YIELD $p1")).WithParams(map[string]any{"p1": int64(1)}).WillFail(errors.New("broken pipe"))
+
+	records := make([]*serveRecord, 0)
+	err := db.Raw(`GO FROM "player100" OVER serve YIELD $$ AS v, edge AS e`).Find(&records)
+	if assert.NoError(t, err) && assert.Len(t, records, 1) {
+		r := records[0]
+		assert.Equal(t, &player{VID: "player100", Name: "Tim Duncan", Age: 42}, r.Player)
+		assert.Equal(t, &serve{SrcID: "player100", DstID: "team204", Rank: 1, StartYear: 1997}, r.Serve)
+		assert.Equal(t, []string{"spurs"}, r.Teams)
+		assert.ElementsMatch(t, []int{98, 99}, r.Scores)
+		assert.Equal(t, map[string]string{"position": "PF"}, r.Attrs)
+		assert.True(t, updated.Equal(r.Updated))
+		assert.Nil(t, r.Deleted)
+	}
+
+	err = db.Raw(`DELETE VERTEX "player100"`).Exec()
+	assert.ErrorIs(t, err, &norm.Error{Code: nebula.ErrorCode_E_EXECUTION_ERROR})
+
+	err = db.Raw("YIELD ?", 1).Exec()
+	assert.EqualError(t, err, "broken pipe")
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// statements not expected
+	assert.Error(t, db.Raw("YIELD 1").Exec())
+}
+
+func TestMockMatch(t *testing.T) {
+	db, mock := New(t)
+	mock.ExpectExec(`"player100"`)
+	mock.ExpectQuery(`"player101"`)
+
+	// the statement does not match the next expectation
+	assert.Error(t, db.Raw(`FETCH PROP ON player "player101" YIELD vertex AS v`).Exec())
+	mock.MatchExpectationsInOrder(false)
+	// reads do not match ExpectExec
+	assert.Error(t, db.Raw(`FETCH PROP ON player "player100" YIELD vertex AS v`).Exec())
+	// the first unmet expectation accepting the statement is matched
+	assert.NoError(t, db.Raw(`FETCH PROP ON player "player101" YIELD vertex AS v`).Exec())
+	assert.NoError(t, db.Raw(`DELETE VERTEX "player100"`).Exec())
+}
+
+// fakeTB records the errors reported by the mock
+type fakeTB struct {
+	errs     []string
+	cleanups []func()
+}
+
+func (t *fakeTB) Helper() {}
+
+func (t *fakeTB) Errorf(format string, args ...any) {
+	t.errs = append(t.errs, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeTB) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func TestExpectationsWereMet(t *testing.T) {
+	tb := new(fakeTB)
+	db, mock := New(tb)
+	mock.ExpectExec(`^INSERT`)
+	mock.ExpectQuery(`^LOOKUP`).WithParams(map[string]any{"name": "Tim Duncan"})
+	mock.ExpectQuery(`^GO`).WillReturnRows([]string{"id"}, []any{1, 2})
+	assert.Len(t, tb.errs, 1)
+
+	assert.NoError(t, db.Raw(`INSERT VERTEX player(name) VALUES "player100":("Tim Duncan")`).Exec())
+	assert.NoError(t, db.Close())
+	assert.Error(t, db.Raw(`LOOKUP ON player`).Exec())
+
+	err := mock.ExpectationsWereMet()
+	assert.EqualError(t, err, "normtest: 2 expectations were not met:\n\tquery matching \"^LOOKUP\" with params map[name:Tim Duncan]\n\tquery matching \"^GO\"")
+	for _, f := range tb.cleanups {
+		f()
+	}
+	assert.Len(t, tb.errs, 2)
+
+	_, err = Value(struct{}{})
+	assert.Error(t, err)
+	_, err = Value(map[int]any{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+package normtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/haysons/norm"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Recorder is an implementation of norm.Executor, which executes statements through the underlying executor and
+// records the statements along with their results. the fixtures are saved to the file when it is closed, so that
+// they can be replayed by Replayer without nebula graph.
+//
+//	pool, err := nebula.NewSessionPool(*poolConf, nebula.DefaultLogger{})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	db, err := norm.OpenWithExecutor(conf, normtest.NewRecorder(t, pool, "testdata/players.json"))
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer db.Close()
+type Recorder struct {
+	t        TB
+	executor norm.Executor
+	path     string
+	mu       sync.Mutex
+	fixtures []*Fixture
+}
+
+var _ norm.Executor = (*Recorder)(nil)
+
+// NewRecorder creates a recorder which saves the fixtures to path, the file is overwritten
+func NewRecorder(t TB, executor norm.Executor, path string) *Recorder {
+	return &Recorder{t: t, executor: executor, path: path, fixtures: make([]*Fixture, 0)}
+}
+
+// Execute executes the statement through the underlying executor and records it
+func (r *Recorder) Execute(stmt string) (*nebula.ResultSet, error) {
+	return r.ExecuteWithParameter(stmt, nil)
+}
+
+// ExecuteWithParameter executes the statement with the parameters through the underlying executor and records it
+func (r *Recorder) ExecuteWithParameter(stmt string, params map[string]any) (*nebula.ResultSet, error) {
+	var (
+		res *nebula.ResultSet
+		err error
+	)
+	if len(params) == 0 {
+		res, err = r.executor.Execute(stmt)
+	} else {
+		res, err = r.executor.ExecuteWithParameter(stmt, params)
+	}
+
+	fixture, recordErr := newFixture(stmt, params, res, err)
+	if recordErr != nil {
+		r.t.Errorf("%v", recordErr)
+		return res, err
+	}
+	r.mu.Lock()
+	r.fixtures = append(r.fixtures, fixture)
+	r.mu.Unlock()
+	return res, err
+}
+
+// Save saves the fixtures recorded so far to the file
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.fixtures, "", "  ")
+	if err != nil {
+		return fmt.Errorf("normtest: marshal fixtures failed: %v", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("normtest: create fixture dir failed: %v", err)
+	}
+	if err = os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("normtest: write fixtures failed: %v", err)
+	}
+	return nil
+}
+
+// Close saves the fixtures and closes the underlying executor, the test fails if the fixtures can not be saved
+func (r *Recorder) Close() {
+	if err := r.Save(); err != nil {
+		r.t.Errorf("%v", err)
+	}
+	r.executor.Close()
+}
+
+// Replayer is an implementation of norm.Executor, which returns the results recorded by Recorder instead of
+// executing the statements. the statements are matched by their normalized text and parameters, the statements
+// executed more than once return their results in the recorded order, and the last one is repeated after that.
+//
+//	db, err := norm.OpenWithExecutor(conf, normtest.NewReplayer(t, "testdata/players.json"))
+type Replayer struct {
+	mu       sync.Mutex
+	fixtures map[string][]*Fixture
+}
+
+var _ norm.Executor = (*Replayer)(nil)
+
+// NewReplayer creates a replayer with the fixtures of the file, the test fails if the file can not be loaded
+func NewReplayer(t TB, path string) *Replayer {
+	t.Helper()
+	r := &Replayer{fixtures: make(map[string][]*Fixture)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("normtest: read fixtures failed: %v", err)
+		return r
+	}
+	fixtures := make([]*Fixture, 0)
+	if err = json.Unmarshal(data, &fixtures); err != nil {
+		t.Errorf("normtest: unmarshal fixtures of %s failed: %v", path, err)
+		return r
+	}
+	for _, fixture := range fixtures {
+		key := fixtureKey(fixture.NGQL, fixture.Params)
+		r.fixtures[key] = append(r.fixtures[key], fixture)
+	}
+	return r
+}
+
+// Execute returns the recorded result of the statement
+func (r *Replayer) Execute(stmt string) (*nebula.ResultSet, error) {
+	return r.ExecuteWithParameter(stmt, nil)
+}
+
+// ExecuteWithParameter returns the recorded result of the statement with the parameters, an error is returned if
+// it was not recorded
+func (r *Replayer) ExecuteWithParameter(stmt string, params map[string]any) (*nebula.ResultSet, error) {
+	key := fixtureKey(stmt, marshalParams(params))
+	r.mu.Lock()
+	fixtures := r.fixtures[key]
+	if len(fixtures) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("normtest: statement was not recorded, params: %v, statement: %s", params, stmt)
+	}
+	fixture := fixtures[0]
+	if len(fixtures) > 1 {
+		r.fixtures[key] = fixtures[1:]
+	}
+	r.mu.Unlock()
+
+	if fixture.Err != "" {
+		return nil, fmt.Errorf("normtest: replayed error: %s", fixture.Err)
+	}
+	return fixture.resultSet()
+}
+
+// Close does nothing, the fixtures can be replayed until the replayer is dropped
+func (r *Replayer) Close() {}
+
+// marshalParams marshals the parameters to json, the keys of the map are sorted so that the result is stable
+func marshalParams(params map[string]any) json.RawMessage {
+	if len(params) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		// fall back to the formatted params, which are still stable since fmt sorts the keys of maps
+		return json.RawMessage(fmt.Sprintf("%q", fmt.Sprint(params)))
+	}
+	return data
+}
+
+// fixtureKey returns the key to match the statements
+func fixtureKey(nGQL string, params json.RawMessage) string {
+	var b bytes.Buffer
+	if err := json.Compact(&b, params); err != nil {
+		b.Reset()
+		b.Write(params)
+	}
+	return NormalizeNGQL(nGQL) + "\x00" + b.String()
+}
+
+// NormalizeNGQL normalizes the nGQL for matching, the consecutive white spaces outside quotes are collapsed into a
+// single space, and the leading and trailing white spaces and semicolons are trimmed.
+func NormalizeNGQL(nGQL string) string {
+	var (
+		b     strings.Builder
+		quote rune
+		space bool
+	)
+	b.Grow(len(nGQL))
+	runes := []rune(strings.TrimRight(strings.TrimSpace(nGQL), "; \t\r\n"))
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		if quote != 0 {
+			b.WriteRune(c)
+			if c == '\\' && i+1 < len(runes) {
+				i++
+				b.WriteRune(runes[i])
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if unicode.IsSpace(c) {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		if c == '"' || c == '\'' || c == '`' {
+			quote = c
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package normtest
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/haysons/norm"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "players.json")
+	conf := &norm.Config{ParameterizedQuery: true}
+	silent := norm.WithLogger(logger.Default.LogMode(logger.SilentLevel))
+	vertex := Vertex{VID: "player100", Tags: []Tag{{Name: "player", Props: map[string]any{"name": "Tim Duncan", "age": 42}}}}
+
+	mock := NewMock(t)
+	mock.ExpectQuery(`^LOOKUP`).WillReturnRows([]string{"v"}, []any{vertex})
+	mock.ExpectQuery(`^LOOKUP`).WillReturnRows([]string{"v"})
+	mock.ExpectQuery(`^FETCH`).WillReturnError(nebula.ErrorCode_E_SEMANTIC_ERROR, "SemanticError")
+	mock.ExpectExec(`^DELETE`).WillFail(errors.New("broken pipe"))
+	mock.ExpectQuery(`^YIELD`).WithParams(map[string]any{"p1": int64(1)}).WillReturnRows([]string{"n"}, []any{1})
+	db, err := norm.OpenWithExecutor(conf, NewRecorder(t, mock, path), silent)
+	assert.NoError(t, err)
+	players := make([]*player, 0)
+	assert.NoError(t, db.Lookup("player").Yield("vertex AS v").FindCol("v", &players))
+	assert.NoError(t, db.Lookup("player").Yield("vertex AS v").FindCol("v", &players))
+	assert.True(t, norm.IsSemanticError(db.Raw(`FETCH PROP ON player "player100" YIELD vertex AS v`).Exec()))
+	assert.Error(t, db.Raw(`DELETE VERTEX "player100"`).Exec())
+	var n int
+	assert.NoError(t, db.Raw("YIELD ?", 1).FindCol("n", &n))
+	assert.NoError(t, db.Close())
+
+	db, err = norm.OpenWithExecutor(conf, NewReplayer(t, path), silent)
+	assert.NoError(t, err)
+	players = make([]*player, 0)
+	assert.NoError(t, db.Raw("LOOKUP ON player\n  YIELD  vertex AS v ; ").FindCol("v", &players))
+	assert.Equal(t, []*player{{VID: "player100", Name: "Tim Duncan", Age: 42}}, players)
+	// the results of the statement executed more than once are returned in order, the last one is repeated
+	for i := 0; i < 2; i++ {
+		players = make([]*player, 0)
+		assert.NoError(t, db.Lookup("player").Yield("vertex AS v").FindCol("v", &players))
+		assert.Empty(t, players)
+	}
+	assert.True(t, norm.IsSemanticError(db.Raw(`FETCH PROP ON player "player100" YIELD vertex AS v`).Exec()))
+	assert.EqualError(t, db.Raw(`DELETE VERTEX "player100"`).Exec(), "normtest: replayed error: broken pipe")
+	n = 0
+	assert.NoError(t, db.Raw("YIELD ?", 1).FindCol("n", &n))
+	assert.Equal(t, 1, n)
+	assert.Error(t, db.Raw("YIELD ?", 2).FindCol("n", &n))
+	assert.Error(t, db.Raw(`FETCH PROP ON player "player101" YIELD vertex AS v`).Exec())
+
+	tb := new(fakeTB)
+	NewReplayer(tb, filepath.Join(t.TempDir(), "missing.json"))
+	assert.Len(t, tb.errs, 1)
+}
+
+func TestFixtureValue(t *testing.T) {
+	vertex := Vertex{VID: int64(100), Tags: []Tag{{Name: "player", Props: map[string]any{"name": "Tim Duncan"}}}}
+	edge := Edge{Src: "player100", Dst: "team204", Name: "serve", Rank: 1, Props: map[string]any{"start_year": 1997}}
+	values := []any{
+		nil, true, 42, 3.14, "Tim Duncan", time.Date(2024, 8, 20, 11, 16, 30, 10000, time.UTC),
+		nebulaType.Date{Year: 2024, Month: 8, Day: 20}, nebulaType.Time{Hour: 11, Minute: 16, Sec: 30},
+		vertex, edge, []any{}, []any{1, "a", []int{2}}, Set{"a", "b"}, map[string]any{}, map[string]any{"a": []any{edge}},
+	}
+	for _, value := range values {
+		want, err := Value(value)
+		assert.NoError(t, err)
+		fv, err := encodeValue(want)
+		assert.NoError(t, err)
+		data, err := json.Marshal(fv)
+		assert.NoError(t, err)
+		decoded := new(FixtureValue)
+		assert.NoError(t, json.Unmarshal(data, decoded))
+		got, err := decodeValue(decoded)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got, string(data))
+	}
+
+	path := &nebulaType.Value{PVal: &nebulaType.Path{
+		Src:   &nebulaType.Vertex{Vid: &nebulaType.Value{SVal: []byte("player100")}},
+		Steps: []*nebulaType.Step{{Dst: &nebulaType.Vertex{Vid: &nebulaType.Value{SVal: []byte("team204")}}, Type: -1, Name: []byte("serve"), Props: map[string]*nebulaType.Value{}}},
+	}}
+	fv, err := encodeValue(path)
+	assert.NoError(t, err)
+	got, err := decodeValue(fv)
+	assert.NoError(t, err)
+	assert.Equal(t, path, got)
+
+	_, err = decodeValue(new(FixtureValue))
+	assert.Error(t, err)
+}
+
+func TestNormalizeNGQL(t *testing.T) {
+	tests := []struct {
+		nGQL string
+		want string
+	}{
+		{nGQL: "  GO FROM \"player100\"\n\tOVER follow ;\n", want: `GO FROM "player100" OVER follow`},
+		{nGQL: `YIELD "a  b",  'c\'  d'`, want: `YIELD "a  b", 'c\'  d'`},
+		{nGQL: "USE `my  space`;  YIELD 1;", want: "USE `my  space`; YIELD 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.nGQL, func(t *testing.T) {
+			assert.Equal(t, tt.want, NormalizeNGQL(tt.nGQL))
+		})
+	}
+}
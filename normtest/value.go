@@ -0,0 +1,222 @@
+package normtest
+
+import (
+	"fmt"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"reflect"
+	"time"
+)
+
+// Vertex is a vertex returned by the mock, eg: the value of 'vertex' in LOOKUP or FETCH statements
+type Vertex struct {
+	// VID the vertex id, a string or an integer
+	VID any
+
+	// Tags the tags of the vertex
+	Tags []Tag
+}
+
+// Tag is a tag of the vertex
+type Tag struct {
+	// Name the tag name
+	Name string
+
+	// Props the properties of the tag
+	Props map[string]any
+}
+
+// Edge is an edge returned by the mock, eg: the value of 'edge' in GO statements
+type Edge struct {
+	// Src the vertex id of the source vertex
+	Src any
+
+	// Dst the vertex id of the destination vertex
+	Dst any
+
+	// Name the edge type name
+	Name string
+
+	// Rank the rank of the edge
+	Rank int64
+
+	// Props the properties of the edge
+	Props map[string]any
+}
+
+// Set is a set returned by the mock, a slice is returned as a list
+type Set []any
+
+// Value converts the go value into a nebula value, the supported values are nil, bool, integers, floats, string,
+// time.Time (as datetime), nebula Date, Time and DateTime, Vertex, Edge, Set, slices (as list), maps keyed by
+// string (as map) and *nebula.Value.
+func Value(v any) (*nebulaType.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		null := nebulaType.NullType___NULL__
+		return &nebulaType.Value{NVal: &null}, nil
+	case *nebulaType.Value:
+		return v, nil
+	case time.Time:
+		v = v.UTC()
+		return &nebulaType.Value{DtVal: &nebulaType.DateTime{
+			Year:     int16(v.Year()),
+			Month:    int8(v.Month()),
+			Day:      int8(v.Day()),
+			Hour:     int8(v.Hour()),
+			Minute:   int8(v.Minute()),
+			Sec:      int8(v.Second()),
+			Microsec: int32(v.Nanosecond() / 1000),
+		}}, nil
+	case nebulaType.Date:
+		return &nebulaType.Value{DVal: &v}, nil
+	case nebulaType.Time:
+		return &nebulaType.Value{TVal: &v}, nil
+	case nebulaType.DateTime:
+		return &nebulaType.Value{DtVal: &v}, nil
+	case Vertex:
+		return vertexValue(v)
+	case *Vertex:
+		return vertexValue(*v)
+	case Edge:
+		return edgeValue(v)
+	case *Edge:
+		return edgeValue(*v)
+	case Set:
+		values, err := listValues(reflect.ValueOf([]any(v)))
+		if err != nil {
+			return nil, err
+		}
+		return &nebulaType.Value{UVal: &nebulaType.NSet{Values: values}}, nil
+	}
+
+	value := reflect.ValueOf(v)
+	switch value.Kind() {
+	case reflect.Bool:
+		b := value.Bool()
+		return &nebulaType.Value{BVal: &b}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i := value.Int()
+		return &nebulaType.Value{IVal: &i}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		i := int64(value.Uint())
+		return &nebulaType.Value{IVal: &i}, nil
+	case reflect.Float32, reflect.Float64:
+		f := value.Float()
+		return &nebulaType.Value{FVal: &f}, nil
+	case reflect.String:
+		return &nebulaType.Value{SVal: []byte(value.String())}, nil
+	case reflect.Slice, reflect.Array:
+		values, err := listValues(value)
+		if err != nil {
+			return nil, err
+		}
+		return &nebulaType.Value{LVal: &nebulaType.NList{Values: values}}, nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("normtest: map key must be string, got %s", value.Type())
+		}
+		kvs := make(map[string]*nebulaType.Value, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			elem, err := Value(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			kvs[iter.Key().String()] = elem
+		}
+		return &nebulaType.Value{MVal: &nebulaType.NMap{Kvs: kvs}}, nil
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return Value(nil)
+		}
+		return Value(value.Elem().Interface())
+	default:
+	}
+	return nil, fmt.Errorf("normtest: can not convert golang type %T into nebula value", v)
+}
+
+func listValues(value reflect.Value) ([]*nebulaType.Value, error) {
+	values := make([]*nebulaType.Value, 0, value.Len())
+	for i := 0; i < value.Len(); i++ {
+		elem, err := Value(value.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, elem)
+	}
+	return values, nil
+}
+
+func propValues(props map[string]any) (map[string]*nebulaType.Value, error) {
+	values := make(map[string]*nebulaType.Value, len(props))
+	for name, prop := range props {
+		value, err := Value(prop)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+func vertexValue(v Vertex) (*nebulaType.Value, error) {
+	vid, err := Value(v.VID)
+	if err != nil {
+		return nil, err
+	}
+	tags := make([]*nebulaType.Tag, 0, len(v.Tags))
+	for _, tag := range v.Tags {
+		props, err := propValues(tag.Props)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, &nebulaType.Tag{Name: []byte(tag.Name), Props: props})
+	}
+	return &nebulaType.Value{VVal: &nebulaType.Vertex{Vid: vid, Tags: tags}}, nil
+}
+
+func edgeValue(e Edge) (*nebulaType.Value, error) {
+	src, err := Value(e.Src)
+	if err != nil {
+		return nil, err
+	}
+	dst, err := Value(e.Dst)
+	if err != nil {
+		return nil, err
+	}
+	props, err := propValues(e.Props)
+	if err != nil {
+		return nil, err
+	}
+	// a positive edge type means the edge is returned in its own direction
+	return &nebulaType.Value{EVal: &nebulaType.Edge{
+		Src:     src,
+		Dst:     dst,
+		Type:    1,
+		Name:    []byte(e.Name),
+		Ranking: e.Rank,
+		Props:   props,
+	}}, nil
+}
+
+// dataSet builds the data set of the result from the column names and the rows
+func dataSet(cols []string, rows [][]any) (*nebulaType.DataSet, error) {
+	ds := &nebulaType.DataSet{
+		ColumnNames: make([][]byte, 0, len(cols)),
+		Rows:        make([]*nebulaType.Row, 0, len(rows)),
+	}
+	for _, col := range cols {
+		ds.ColumnNames = append(ds.ColumnNames, []byte(col))
+	}
+	for i, row := range rows {
+		if len(row) != len(cols) {
+			return nil, fmt.Errorf("normtest: row %d has %d values, expected %d columns", i, len(row), len(cols))
+		}
+		values, err := listValues(reflect.ValueOf(row))
+		if err != nil {
+			return nil, err
+		}
+		ds.Rows = append(ds.Rows, &nebulaType.Row{Values: values})
+	}
+	return ds, nil
+}
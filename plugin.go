@@ -0,0 +1,106 @@
+package norm
+
+import (
+	"context"
+	"fmt"
+	"github.com/haysons/norm/statement"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+// Query is a built statement on its way to nebula graph, it is passed through the handlers of all plugins
+// before being executed.
+type Query struct {
+	// NGQL the nGQL statement to be executed, plugins may rewrite it
+	NGQL string
+
+	// Params query parameters of the statement, only present when the statement is parameterized
+	Params map[string]any
+
+	// Statement the statement from which NGQL was built
+	Statement *statement.Statement
+
+	// Space the graph space in which the statement is executed
+	Space string
+
+	db *DB
+}
+
+// Handler executes the query and returns the result
+type Handler func(ctx context.Context, query *Query) (*nebula.ResultSet, error)
+
+// Plugin extends norm by wrapping the execution of statements, eg: metrics, tracing, statement rewriting, caching
+// or guards. It applies to every statement executed by DB and Migrator.
+//
+//	type guard struct{}
+//
+//	func (g guard) Name() string {
+//		return "guard"
+//	}
+//
+//	func (g guard) Intercept(next norm.Handler) norm.Handler {
+//		return func(ctx context.Context, query *norm.Query) (*nebula.ResultSet, error) {
+//			if strings.HasPrefix(query.NGQL, "DROP") {
+//				return nil, errors.New("drop is not allowed")
+//			}
+//			return next(ctx, query)
+//		}
+//	}
+type Plugin interface {
+	// Name returns the name of the plugin, which must be unique within a DB
+	Name() string
+
+	// Intercept wraps the next handler, the returned handler is called instead of next. the plugin may inspect or
+	// modify the query, skip next to return its own result, and inspect the result or error returned by next.
+	Intercept(next Handler) Handler
+}
+
+// pluginChain the plugins registered to a DB and the handler chain built from them, it is shared by all sessions
+// created from the DB
+type pluginChain struct {
+	plugins []Plugin
+	handler Handler
+}
+
+// Use registers plugins, the plugin registered first is the outermost one in the handler chain.
+// plugins are shared by all sessions created from the DB, so they should be registered before the DB is used.
+func (db *DB) Use(plugins ...Plugin) error {
+	if db.chain == nil {
+		db.chain = new(pluginChain)
+	}
+	registered := make(map[string]bool, len(db.chain.plugins)+len(plugins))
+	for _, p := range db.chain.plugins {
+		registered[p.Name()] = true
+	}
+	for _, plugin := range plugins {
+		if registered[plugin.Name()] {
+			return fmt.Errorf("norm: %w, name: %s", ErrPluginRegistered, plugin.Name())
+		}
+		registered[plugin.Name()] = true
+	}
+	db.chain.plugins = append(db.chain.plugins, plugins...)
+	handler := Handler(executeQuery)
+	for i := len(db.chain.plugins) - 1; i >= 0; i-- {
+		handler = db.chain.plugins[i].Intercept(handler)
+	}
+	db.chain.handler = handler
+	return nil
+}
+
+// executeQuery is the innermost handler, which actually executes the query and retries it according to the retry
+// policy. if the result is not succeed, the result is returned along with an *Error, so that plugins are able to
+// see the failure.
+func executeQuery(ctx context.Context, query *Query) (*nebula.ResultSet, error) {
+	if query.db.isDryRun() && !query.db.force {
+		return nebula.GenResultSet(&graph.ExecutionResponse{})
+	}
+	return query.db.executeRetry(ctx, query)
+}
+
+// handle passes the query through the handler chain of the registered plugins
+func (db *DB) handle(ctx context.Context, query *Query) (*nebula.ResultSet, error) {
+	if db.chain == nil || db.chain.handler == nil {
+		return executeQuery(ctx, query)
+	}
+	return db.chain.handler(ctx, query)
+}
@@ -0,0 +1,89 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"testing"
+)
+
+type recordPlugin struct {
+	name  string
+	calls *[]string
+	skip  bool
+}
+
+func (p recordPlugin) Name() string {
+	return p.name
+}
+
+func (p recordPlugin) Intercept(next Handler) Handler {
+	return func(ctx context.Context, query *Query) (*nebula.ResultSet, error) {
+		*p.calls = append(*p.calls, p.name)
+		if p.skip {
+			return nil, nil
+		}
+		return next(ctx, query)
+	}
+}
+
+func TestUse(t *testing.T) {
+	calls := make([]string, 0)
+	db := &DB{conf: &Config{}}
+	assert.NoError(t, db.Use(recordPlugin{name: "p1", calls: &calls}, recordPlugin{name: "p2", calls: &calls}))
+	assert.NoError(t, db.Use(recordPlugin{name: "p3", calls: &calls, skip: true}))
+
+	err := db.Use(recordPlugin{name: "p4", calls: &calls}, recordPlugin{name: "p1", calls: &calls})
+	assert.True(t, errors.Is(err, ErrPluginRegistered))
+	assert.Len(t, db.chain.plugins, 3)
+
+	_, err = db.handle(context.Background(), &Query{NGQL: "YIELD 1", db: db})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"p1", "p2", "p3"}, calls)
+}
+
+func TestUseSharedConfig(t *testing.T) {
+	calls := make([]string, 0)
+	conf := &Config{}
+	db1, err := OpenWithExecutor(conf, &fakeExecutor{}, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+	db2, err := OpenWithExecutor(conf, &fakeExecutor{}, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+
+	// the plugins are registered to the DB, not to the Config it is opened with
+	assert.NoError(t, db1.Use(recordPlugin{name: "p1", calls: &calls}))
+	assert.NoError(t, db2.Raw("YIELD 1").Exec())
+	assert.Empty(t, calls)
+	assert.NoError(t, db1.WithContext(context.Background()).Raw("YIELD 1").Exec())
+	assert.Equal(t, []string{"p1"}, calls)
+	assert.NoError(t, db2.Use(recordPlugin{name: "p1", calls: &calls}))
+}
+
+// stubPlugin replaces the execution of statements, so that the statements can be tested without nebula graph
+type stubPlugin func(ctx context.Context, query *Query) (*nebula.ResultSet, error)
+
+func (p stubPlugin) Name() string {
+	return "stub"
+}
+
+func (p stubPlugin) Intercept(_ Handler) Handler {
+	return Handler(p)
+}
+
+// capturePlugin captures the nGQL of the queries passed through it
+type capturePlugin struct {
+	nGQLs *[]string
+}
+
+func (p capturePlugin) Name() string {
+	return "capture"
+}
+
+func (p capturePlugin) Intercept(next Handler) Handler {
+	return func(ctx context.Context, query *Query) (*nebula.ResultSet, error) {
+		*p.nGQLs = append(*p.nGQLs, query.NGQL)
+		return next(ctx, query)
+	}
+}
@@ -0,0 +1,95 @@
+package norm
+
+import (
+	"github.com/haysons/norm/statement"
+	"sync/atomic"
+)
+
+// routeTarget the cluster on which the statement is executed
+type routeTarget int
+
+const (
+	routeAuto routeTarget = iota
+	routePrimary
+	routeReplica
+)
+
+// replicaSet the executors of the read replica clusters, which are used in turn
+type replicaSet struct {
+	executors []Executor
+	next      uint64
+}
+
+// newReplicaSet creates the session pools of the replica clusters, nil is returned if there is no replica
+func newReplicaSet(conf *Config) (*replicaSet, error) {
+	if len(conf.Replicas) == 0 {
+		return nil, nil
+	}
+	replicas := &replicaSet{executors: make([]Executor, 0, len(conf.Replicas))}
+	for _, replica := range conf.Replicas {
+		username, password := replica.Username, replica.Password
+		if username == "" {
+			username, password = conf.Username, conf.Password
+		}
+		pool, err := newSessionPool(conf, replica.Addresses, username, password)
+		if err != nil {
+			replicas.close()
+			return nil, err
+		}
+		replicas.executors = append(replicas.executors, pool)
+	}
+	return replicas, nil
+}
+
+// pick returns the executor of the next replica
+func (r *replicaSet) pick() Executor {
+	n := atomic.AddUint64(&r.next, 1)
+	return r.executors[(n-1)%uint64(len(r.executors))]
+}
+
+func (r *replicaSet) close() {
+	if r == nil {
+		return
+	}
+	for _, executor := range r.executors {
+		executor.Close()
+	}
+}
+
+// UsePrimary executes the current statement on the primary cluster, eg: read your own writes
+func (db *DB) UsePrimary() (tx *DB) {
+	tx = db.getInstance()
+	tx.target = routePrimary
+	return
+}
+
+// UseReplica executes the current statement on a replica cluster, even if it is not read-only.
+// it has no effect if there is no replica.
+func (db *DB) UseReplica() (tx *DB) {
+	tx = db.getInstance()
+	tx.target = routeReplica
+	return
+}
+
+// executorFor returns the executor by which the nGQL is executed, read-only statements (GO, FETCH, LOOKUP, MATCH...)
+// are routed to the replicas, writes and DDL are routed to the primary. the nGQL is classified by its text,
+// so that Raw statements are routed as well.
+func (db *DB) executorFor(nGQL string) Executor {
+	if db.pinned != nil {
+		return db.pinned
+	}
+	if db.replicas == nil {
+		return db.executor
+	}
+	switch db.target {
+	case routePrimary:
+		return db.executor
+	case routeReplica:
+		return db.replicas.pick()
+	default:
+		if statement.IsReadOnly(nGQL) {
+			return db.replicas.pick()
+		}
+		return db.executor
+	}
+}
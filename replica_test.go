@@ -0,0 +1,26 @@
+package norm
+
+import (
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"testing"
+)
+
+func TestRoute(t *testing.T) {
+	primary := new(nebula.SessionPool)
+	replica1, replica2 := new(nebula.SessionPool), new(nebula.SessionPool)
+	db := &DB{conf: &Config{}, executor: primary, clone: 1}
+
+	read := `GO FROM "player102" OVER serve YIELD dst(edge) AS id;`
+	write := `INSERT VERTEX player(name, age) VALUES "player100":("Tim Duncan", 42);`
+	assert.Same(t, primary, db.executorFor(read))
+
+	db.replicas = &replicaSet{executors: []Executor{replica1, replica2}}
+	assert.Same(t, replica1, db.executorFor(read))
+	assert.Same(t, replica2, db.executorFor(read))
+	assert.Same(t, replica1, db.executorFor(read))
+	assert.Same(t, primary, db.executorFor(write))
+	assert.Same(t, primary, db.UsePrimary().executorFor(read))
+	assert.Same(t, replica2, db.UseReplica().executorFor(write))
+	assert.Same(t, primary, db.UsePrimary().WithContext(db.Context()).Raw(read).executorFor(read))
+}
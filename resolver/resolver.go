@@ -3,6 +3,7 @@ package resolver
 import (
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"strconv"
 	"strings"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/haysons/norm/internal/utils"
 	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
 )
 
 const (
@@ -279,6 +281,11 @@ func ScanSimpleValue(nebulaValue *nebula.ValueWrapper, destValue reflect.Value)
 		if err != nil {
 			return err
 		}
+		if valueIface == nil {
+			// the empty value
+			destValue.SetZero()
+			return nil
+		}
 		destValue.Set(reflect.ValueOf(valueIface))
 		return nil
 	}
@@ -591,6 +598,74 @@ func FormatSimpleValue(sdkType string, value reflect.Value) (string, error) {
 	return "", fmt.Errorf("norm: format value failed, golang type: %s, nebula type: %s", value.Type(), sdkType)
 }
 
+// FormatParamValue converts variable values to the parameter values accepted by nebula.SessionPool.ExecuteWithParameter,
+// that is bool, int64, float64, string, nil, nebula.DateTime, []any and map[string]any.
+func FormatParamValue(value reflect.Value) (any, error) {
+	switch value.Kind() {
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v := value.Uint()
+		if v > math.MaxInt64 {
+			return nil, fmt.Errorf("norm: format param value failed, %d overflows int64", v)
+		}
+		return int64(v), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Struct:
+		if t, ok := value.Interface().(time.Time); ok {
+			// datetime is stored in UTC on the server side
+			t = t.UTC()
+			return nebulaType.DateTime{
+				Year:     int16(t.Year()),
+				Month:    int8(t.Month()),
+				Day:      int8(t.Day()),
+				Hour:     int8(t.Hour()),
+				Minute:   int8(t.Minute()),
+				Sec:      int8(t.Second()),
+				Microsec: int32(t.Nanosecond() / 1000),
+			}, nil
+		}
+	case reflect.Slice, reflect.Array:
+		list := make([]any, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			elem, err := FormatParamValue(value.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, elem)
+		}
+		return list, nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("norm: format param value failed, can not convert map key to string")
+		}
+		m := make(map[string]any, value.Len())
+		mapIter := value.MapRange()
+		for mapIter.Next() {
+			elem, err := FormatParamValue(mapIter.Value())
+			if err != nil {
+				return nil, err
+			}
+			m[mapIter.Key().String()] = elem
+		}
+		return m, nil
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil, nil
+		}
+		return FormatParamValue(value.Elem())
+	case reflect.Invalid:
+		return nil, nil
+	default:
+	}
+	return nil, fmt.Errorf("norm: format param value failed, golang type: %s", value.Type())
+}
+
 // GetValueIface get the nebula graph return value
 func GetValueIface(nebulaValue *nebula.ValueWrapper) (any, error) {
 	switch nebulaValue.GetType() {
@@ -3,6 +3,8 @@ package resolver
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"math"
 	"reflect"
 	"testing"
 	"time"
@@ -158,3 +160,44 @@ func TestFormatSimpleValue(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatParamValue(t *testing.T) {
+	a := "hello"
+	tests := []struct {
+		value   any
+		want    any
+		wantErr bool
+	}{
+		{value: true, want: true},
+		{value: 1, want: int64(1)},
+		{value: int8(-1), want: int64(-1)},
+		{value: uint32(1), want: int64(1)},
+		{value: uint64(math.MaxUint64), wantErr: true},
+		{value: 1.5, want: 1.5},
+		{value: "hello", want: "hello"},
+		{value: &a, want: "hello"},
+		{value: (*int)(nil), want: nil},
+		{value: nil, want: nil},
+		{
+			value: time.Date(2024, 8, 20, 11, 16, 30, 10000, time.UTC),
+			want:  nebulaType.DateTime{Year: 2024, Month: 8, Day: 20, Hour: 11, Minute: 16, Sec: 30, Microsec: 10},
+		},
+		{value: []string{"a", "b"}, want: []any{"a", "b"}},
+		{value: [2]int{1, 2}, want: []any{int64(1), int64(2)}},
+		{value: map[string]any{"d": map[string]int{"age": 18}}, want: map[string]any{"d": map[string]any{"age": int64(18)}}},
+		{value: map[int]int{1: 1}, wantErr: true},
+		{value: struct{}{}, wantErr: true},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("case #%d", i), func(t *testing.T) {
+			got, err := FormatParamValue(reflect.ValueOf(tt.value))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package norm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/haysons/norm/internal/utils"
 	"github.com/haysons/norm/logger"
@@ -9,6 +10,7 @@ import (
 	"github.com/haysons/norm/statement"
 	nebula "github.com/vesoft-inc/nebula-go/v3"
 	"reflect"
+	"time"
 )
 
 // NGQL get this generated statement does not actually execute the statement
@@ -17,50 +19,48 @@ func (db *DB) NGQL() (string, error) {
 	return tx.Statement.NGQL()
 }
 
-// RawResult exec the statement and return the result of nebula-go directly
+// RawResult exec the statement and return the result of nebula-go directly, the error is only returned if the
+// result is not obtained, eg: the connection failed. whether the result is succeed should be checked by
+// res.IsSucceed(), other methods like Exec and Find return an *Error for the failed result instead.
 func (db *DB) RawResult() (*nebula.ResultSet, error) {
-	nGQL, err := db.NGQL()
-	if err != nil {
-		return nil, err
+	res, err := db.execute()
+	var resErr *Error
+	if res != nil && errors.As(err, &resErr) {
+		return res, nil
 	}
-	res, err := db.sessionPool.Execute(nGQL)
-	db.conf.logger.Trace(context.TODO(), &logger.TraceRecord{NGQL: nGQL, Err: err})
 	return res, err
 }
 
 // Exec the statement, but don't care about the result as long as it is used for insert, update, delete operations
 func (db *DB) Exec() error {
-	nGQL, err := db.NGQL()
-	if err != nil {
-		return err
-	}
-	res, err := db.sessionPool.Execute(nGQL)
-	db.conf.logger.Trace(context.TODO(), &logger.TraceRecord{NGQL: nGQL, Err: err})
-	if err != nil {
-		return err
-	}
-	if !res.IsSucceed() {
-		return fmt.Errorf("norm: result is not succeed, err code: %d, msg: %s", res.GetErrorCode(), res.GetErrorMsg())
-	}
-	return nil
+	_, err := db.execute()
+	return err
 }
 
 // Find exec the statement and assign the returned result to the dest variable
 func (db *DB) Find(dest any) error {
-	rawRes, err := db.RawResult()
+	tx := db.getInstance()
+	rawRes, err := tx.execute()
 	if err != nil {
 		return err
 	}
-	return Scan(rawRes, dest)
+	if err = Scan(rawRes, dest); err != nil {
+		return err
+	}
+	return callAfterFind(tx.Context(), dest)
 }
 
 // FindCol parse one column of the result, it is used to easily get the value of a field
 func (db *DB) FindCol(col string, dest any) error {
-	rawRes, err := db.RawResult()
+	tx := db.getInstance()
+	rawRes, err := tx.execute()
 	if err != nil {
 		return err
 	}
-	return Pluck(rawRes, col, dest)
+	if err = Pluck(rawRes, col, dest); err != nil {
+		return err
+	}
+	return callAfterFind(tx.Context(), dest)
 }
 
 // Take get a single test result, if no limit is specified, limit 1 will be added automatically,
@@ -71,16 +71,14 @@ func (db *DB) Take(dest any) error {
 	if lastPart.GetType() != statement.PartTypeLimit {
 		tx.Statement.Limit(1)
 	}
-	nGQL, err := tx.Statement.NGQL()
+	rawRes, err := tx.execute()
 	if err != nil {
 		return err
 	}
-	rawRes, err := db.sessionPool.Execute(nGQL)
-	db.conf.logger.Trace(context.TODO(), &logger.TraceRecord{NGQL: nGQL, Err: err})
-	if err != nil {
+	if err = scan(rawRes, dest, true); err != nil {
 		return err
 	}
-	return scan(rawRes, dest, true)
+	return callAfterFind(tx.Context(), dest)
 }
 
 // TakeCol parse one column of the result, it is used to easily get the value of a field
@@ -91,16 +89,118 @@ func (db *DB) TakeCol(col string, dest any) error {
 	if lastPart.GetType() != statement.PartTypeLimit {
 		tx.Statement.Limit(1)
 	}
-	nGQL, err := tx.Statement.NGQL()
+	rawRes, err := tx.execute()
 	if err != nil {
 		return err
 	}
-	rawRes, err := db.sessionPool.Execute(nGQL)
-	db.conf.logger.Trace(context.TODO(), &logger.TraceRecord{NGQL: nGQL, Err: err})
-	if err != nil {
+	if err = pluck(rawRes, col, dest, true); err != nil {
 		return err
 	}
-	return pluck(rawRes, col, dest, true)
+	return callAfterFind(tx.Context(), dest)
+}
+
+// execute builds the statement of the current session and executes it through the handlers of plugins,
+// every execution path ends up here, so that the context, hooks, plugins and logging are handled in one place.
+func (db *DB) execute() (*nebula.ResultSet, error) {
+	tx := db.getInstance()
+	ctx := tx.Context()
+	// before hooks may modify the values, so they are called before building the statement
+	if err := tx.callBeforeHooks(ctx); err != nil {
+		return nil, err
+	}
+	nGQL, err := tx.Statement.NGQL()
+	if err != nil {
+		return nil, err
+	}
+	if nGQL, err = tx.useSpace(nGQL); err != nil {
+		return nil, err
+	}
+	query := &Query{
+		NGQL:      nGQL,
+		Params:    tx.Statement.Params(),
+		Statement: tx.Statement,
+		Space:     tx.spaceName(),
+		db:        tx,
+	}
+	begin := time.Now()
+	res, err := tx.handle(ctx, query)
+	tx.conf.logger.Trace(ctx, traceRecord(query, res, err, begin))
+	if err != nil {
+		return res, err
+	}
+	if tx.isDryRun() && !tx.force {
+		// nothing is written in dry-run mode, so the after hooks are not called
+		return res, nil
+	}
+	if err = tx.callAfterHooks(ctx); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// traceRecord creates the trace record of the execution of the query
+func traceRecord(query *Query, res *nebula.ResultSet, err error, begin time.Time) *logger.TraceRecord {
+	record := &logger.TraceRecord{
+		NGQL:    query.NGQL,
+		Params:  query.Params,
+		Err:     err,
+		Begin:   begin,
+		Elapsed: time.Since(begin),
+		Space:   query.Space,
+		Kind:    statement.Kind(query.NGQL),
+	}
+	if res != nil {
+		record.Rows = res.GetRowSize()
+		record.Latency = time.Duration(res.GetLatency()) * time.Microsecond
+	}
+	return record
+}
+
+// executeContext executes nGQL and waits for the result until ctx is done. nebula.SessionPool is not aware of
+// the context, so the statement keeps running on the server after cancellation, but the caller is released
+// immediately with ctx.Err().
+func (db *DB) executeContext(ctx context.Context, nGQL string, params map[string]any) (*nebula.ResultSet, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// the context can never be cancelled, there is no need to wait for it
+	if ctx.Done() == nil {
+		return db.executeParams(nGQL, params)
+	}
+	type result struct {
+		res *nebula.ResultSet
+		err error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		res, err := db.executeParams(nGQL, params)
+		resCh <- result{res: res, err: err}
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-resCh:
+		return r.res, r.err
+	}
+}
+
+func (db *DB) executeParams(nGQL string, params map[string]any) (*nebula.ResultSet, error) {
+	defer db.trackInUse()()
+	executor := db.executorFor(nGQL)
+	var (
+		res *nebula.ResultSet
+		err error
+	)
+	if len(params) == 0 {
+		res, err = executor.Execute(nGQL)
+	} else {
+		res, err = executor.ExecuteWithParameter(nGQL, params)
+	}
+	if res == nil && err == nil {
+		// a custom executor returning neither a result nor an error would make the result processing panic
+		return nil, fmt.Errorf("norm: executor returned no result for %s", nGQL)
+	}
+	return res, err
 }
 
 // Scan assign the results to the target variable
@@ -109,8 +209,8 @@ func Scan(rawRes *nebula.ResultSet, dest any) error {
 }
 
 func scan(rawRes *nebula.ResultSet, dest any, raiseNotFound bool) error {
-	if !rawRes.IsSucceed() {
-		return fmt.Errorf("norm: result is not succeed, err code: %d, msg: %s", rawRes.GetErrorCode(), rawRes.GetErrorMsg())
+	if err := newResultError(rawRes, ""); err != nil {
+		return err
 	}
 	if rawRes.GetRowSize() == 0 {
 		if raiseNotFound {
@@ -204,8 +304,8 @@ func Pluck(rawRes *nebula.ResultSet, col string, dest any) error {
 }
 
 func pluck(rawRes *nebula.ResultSet, col string, dest any, raiseNotFound bool) error {
-	if !rawRes.IsSucceed() {
-		return fmt.Errorf("norm: result is not succeed, err code: %d, msg: %s", rawRes.GetErrorCode(), rawRes.GetErrorMsg())
+	if err := newResultError(rawRes, ""); err != nil {
+		return err
 	}
 	if rawRes.GetRowSize() == 0 {
 		if raiseNotFound {
@@ -0,0 +1,116 @@
+package norm
+
+import (
+	"context"
+	"github.com/haysons/norm/statement"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultRetryBaseBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff  = 2 * time.Second
+)
+
+// RetryPolicy retries the statements that failed because of transient failures, eg: the leader changed during
+// storage rebalancing. By default, only reads and idempotent writes are retried, use DB.Retry to override it
+// for a single call.
+type RetryPolicy struct {
+	// MaxAttempts max number of attempts including the first one, retry is disabled if it is not greater than 1
+	MaxAttempts int `json:"max_attempts" yaml:"max_attempts"`
+
+	// BaseBackoff the backoff before the first retry, it doubles after each retry, default is 100ms
+	BaseBackoff time.Duration `json:"base_backoff" yaml:"base_backoff"`
+
+	// MaxBackoff the upper limit of the backoff, default is 2s
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff"`
+
+	// RetryableCodes the error codes to retry on, default is the codes reported by IsRetryable
+	RetryableCodes []nebula.ErrorCode `json:"retryable_codes" yaml:"retryable_codes"`
+}
+
+// Retry overrides the retry policy for the current statement, when enabled the statement is retried even if it is
+// not idempotent, when disabled the statement is executed only once.
+//
+//	err := db.Retry(true).UpdateVertex("player100", props).Exec()
+func (db *DB) Retry(enable bool) (tx *DB) {
+	tx = db.getInstance()
+	tx.retry = &enable
+	return
+}
+
+// shouldRetry reports whether the query is allowed to be retried
+func (db *DB) shouldRetry(nGQL string) bool {
+	policy := db.conf.Retry
+	if policy == nil || policy.MaxAttempts <= 1 {
+		return false
+	}
+	if db.retry != nil {
+		return *db.retry
+	}
+	return statement.IsIdempotent(nGQL)
+}
+
+// isRetryable reports whether the error is a transient failure according to the policy
+func (p *RetryPolicy) isRetryable(err error) bool {
+	if len(p.RetryableCodes) == 0 {
+		return IsRetryable(err)
+	}
+	code, ok := errorCode(err)
+	if !ok {
+		return false
+	}
+	for _, c := range p.RetryableCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the time to wait before the next attempt, half of it is randomized to avoid retrying in lockstep
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	base, maxBackoff := p.BaseBackoff, p.MaxBackoff
+	if base <= 0 {
+		base = defaultRetryBaseBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = defaultRetryMaxBackoff
+	}
+	d := maxBackoff
+	if attempt < 32 && base<<attempt > 0 && base<<attempt < maxBackoff {
+		d = base << attempt
+	}
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// executeRetry executes the query and retries it on transient failures according to the retry policy
+func (db *DB) executeRetry(ctx context.Context, query *Query) (*nebula.ResultSet, error) {
+	res, err := db.executeResult(ctx, query)
+	if err == nil || !db.shouldRetry(query.NGQL) {
+		return res, err
+	}
+	policy := db.conf.Retry
+	for attempt := 1; attempt < policy.MaxAttempts && policy.isRetryable(err); attempt++ {
+		timer := time.NewTimer(policy.backoff(attempt - 1))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		res, err = db.executeResult(ctx, query)
+	}
+	return res, err
+}
+
+// executeResult executes the query, if the result is not succeed, the result is returned along with an *Error
+func (db *DB) executeResult(ctx context.Context, query *Query) (*nebula.ResultSet, error) {
+	res, err := db.executeContext(ctx, query.NGQL, query.Params)
+	if err != nil {
+		return res, err
+	}
+	return res, newResultError(res, query.NGQL)
+}
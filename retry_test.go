@@ -0,0 +1,106 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"testing"
+	"time"
+)
+
+func TestShouldRetry(t *testing.T) {
+	policy := &RetryPolicy{MaxAttempts: 3}
+	tests := []struct {
+		db   *DB
+		nGQL string
+		want bool
+	}{
+		{
+			db:   &DB{conf: &Config{}},
+			nGQL: `FETCH PROP ON player "player100" YIELD vertex AS v;`,
+			want: false,
+		},
+		{
+			db:   &DB{conf: &Config{Retry: &RetryPolicy{MaxAttempts: 1}}},
+			nGQL: `FETCH PROP ON player "player100" YIELD vertex AS v;`,
+			want: false,
+		},
+		{
+			db:   &DB{conf: &Config{Retry: policy}},
+			nGQL: `FETCH PROP ON player "player100" YIELD vertex AS v;`,
+			want: true,
+		},
+		{
+			db:   &DB{conf: &Config{Retry: policy}},
+			nGQL: `INSERT VERTEX IF NOT EXISTS player(name) VALUES "player100":("Tim Duncan");`,
+			want: true,
+		},
+		{
+			db:   &DB{conf: &Config{Retry: policy}},
+			nGQL: `UPDATE VERTEX ON player "player101" SET age = age + 2;`,
+			want: false,
+		},
+		{
+			db:   (&DB{conf: &Config{Retry: policy}}).Retry(true),
+			nGQL: `UPDATE VERTEX ON player "player101" SET age = age + 2;`,
+			want: true,
+		},
+		{
+			db:   (&DB{conf: &Config{Retry: policy}}).Retry(false),
+			nGQL: `FETCH PROP ON player "player100" YIELD vertex AS v;`,
+			want: false,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("case #%d", i), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.db.shouldRetry(tt.nGQL))
+		})
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	leaderChanged := newResultError(newFailedResult(t, nebulaType.ErrorCode_E_LEADER_CHANGED, ""), "")
+	syntaxError := newResultError(newFailedResult(t, nebulaType.ErrorCode_E_SYNTAX_ERROR, ""), "")
+
+	policy := &RetryPolicy{MaxAttempts: 3}
+	assert.True(t, policy.isRetryable(leaderChanged))
+	assert.False(t, policy.isRetryable(syntaxError))
+
+	policy = &RetryPolicy{MaxAttempts: 3, RetryableCodes: []nebula.ErrorCode{nebula.ErrorCode_E_SYNTAX_ERROR}}
+	assert.False(t, policy.isRetryable(leaderChanged))
+	assert.True(t, policy.isRetryable(syntaxError))
+
+	policy = &RetryPolicy{BaseBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+	for attempt, want := range []time.Duration{10, 20, 40, 50, 50} {
+		want *= time.Millisecond
+		for i := 0; i < 10; i++ {
+			backoff := policy.backoff(attempt)
+			assert.True(t, backoff >= want/2 && backoff <= want, "attempt %d, backoff %s", attempt, backoff)
+		}
+	}
+	assert.True(t, policy.backoff(100) <= 50*time.Millisecond)
+}
+
+func TestExecuteRetry(t *testing.T) {
+	executor := &fakeExecutor{res: newFailedResult(t, nebulaType.ErrorCode_E_LEADER_CHANGED, "leader changed")}
+	conf := &Config{Retry: &RetryPolicy{MaxAttempts: 3, BaseBackoff: time.Millisecond}}
+	db, err := OpenWithExecutor(conf, executor, WithLogger(logger.Default.LogMode(logger.SilentLevel)))
+	assert.NoError(t, err)
+	nGQL := `FETCH PROP ON player "player100" YIELD vertex AS v;`
+	err = db.Raw(nGQL).Exec()
+	assert.True(t, errors.Is(err, &Error{Code: nebula.ErrorCode(nebulaType.ErrorCode_E_LEADER_CHANGED)}))
+	assert.Len(t, executor.stmts, 3)
+
+	// the error of the context is returned if it is done during the backoff
+	executor.stmts = nil
+	conf.Retry.BaseBackoff = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = db.WithContext(ctx).Raw(nGQL).Exec()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, executor.stmts, 1)
+}
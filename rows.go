@@ -0,0 +1,120 @@
+package norm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/haysons/norm/resolver"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"reflect"
+)
+
+// Rows is an iterator over the records of the result, the records are scanned one at a time, so that large results
+// are processed without copying all of them into go values. Note that the result itself is returned by nebula graph
+// as a whole, Rows does not stream it from the server.
+//
+//	rows, err := db.Lookup("player").Yield("id(vertex) as vid, properties(vertex).name as name").Rows()
+//	if err != nil {
+//		return err
+//	}
+//	for rows.Next() {
+//		player := new(Player)
+//		if err = rows.Scan(player); err != nil {
+//			return err
+//		}
+//	}
+//	return rows.Err()
+type Rows struct {
+	ctx    context.Context
+	res    *nebula.ResultSet
+	cols   []string
+	index  int
+	record *nebula.Record
+	rv     *resolver.Resolver
+	err    error
+}
+
+// Rows exec the statement and returns an iterator over the records of the result
+func (db *DB) Rows() (*Rows, error) {
+	tx := db.getInstance()
+	rawRes, err := tx.execute()
+	if err != nil {
+		return nil, err
+	}
+	return newRows(tx.Context(), rawRes)
+}
+
+func newRows(ctx context.Context, rawRes *nebula.ResultSet) (*Rows, error) {
+	if err := newResultError(rawRes, ""); err != nil {
+		return nil, err
+	}
+	return &Rows{
+		ctx:   ctx,
+		res:   rawRes,
+		cols:  rawRes.GetColNames(),
+		index: -1,
+		rv:    resolver.NewResolver(),
+	}, nil
+}
+
+// Next prepares the next record for Scan, it returns false if there is no more record or an error occurred,
+// Err should be consulted to distinguish between the two cases.
+func (r *Rows) Next() bool {
+	if r.err != nil || r.res == nil {
+		return false
+	}
+	r.index++
+	if r.index >= r.res.GetRowSize() {
+		r.record = nil
+		return false
+	}
+	r.record, r.err = r.res.GetRowValuesByIndex(r.index)
+	return r.err == nil
+}
+
+// Scan assigns the current record to dest, dest should be a pointer to struct or a map[string]any.
+// the schema of the struct is parsed only once and reused for all records.
+func (r *Rows) Scan(dest any) error {
+	if r.record == nil {
+		return errors.New("norm: Scan called without calling Next")
+	}
+	switch v := dest.(type) {
+	case *map[string]any:
+		if *v == nil {
+			*v = make(map[string]any, len(r.cols))
+		}
+		if err := scanIntoMap(r.record, r.cols, *v); err != nil {
+			return err
+		}
+	case map[string]any:
+		if err := scanIntoMap(r.record, r.cols, v); err != nil {
+			return err
+		}
+	default:
+		destValue := reflect.ValueOf(dest)
+		if destValue.Kind() != reflect.Ptr || destValue.IsNil() {
+			return fmt.Errorf("norm: %w, scan dest should be pointer to struct", ErrInvalidValue)
+		}
+		if err := r.rv.ScanRecord(r.record, r.cols, destValue); err != nil {
+			return err
+		}
+	}
+	return callAfterFind(r.ctx, dest)
+}
+
+// Columns returns the column names of the result
+func (r *Rows) Columns() []string {
+	return r.cols
+}
+
+// Err returns the error encountered during iteration
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// Close releases the result, Next returns false after Close is called
+func (r *Rows) Close() error {
+	r.res = nil
+	r.record = nil
+	return nil
+}
@@ -0,0 +1,72 @@
+package norm
+
+import (
+	"context"
+	"github.com/haysons/norm/logger"
+	"github.com/stretchr/testify/assert"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	nebulaType "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+	"testing"
+)
+
+func newPlayersResult(t *testing.T, names ...string) *nebula.ResultSet {
+	rows := make([]*nebulaType.Row, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, &nebulaType.Row{Values: []*nebulaType.Value{
+			{SVal: []byte("player_" + name)},
+			{SVal: []byte(name)},
+		}})
+	}
+	res, err := nebula.GenResultSet(&graph.ExecutionResponse{
+		Data: &nebulaType.DataSet{ColumnNames: [][]byte{[]byte("vid"), []byte("name")}, Rows: rows},
+	})
+	assert.NoError(t, err)
+	return res
+}
+
+type rowsPlayer struct {
+	VID   string `norm:"col:vid"`
+	Name  string `norm:"col:name"`
+	found bool
+}
+
+func (p *rowsPlayer) AfterFind(_ context.Context) error {
+	p.found = true
+	return nil
+}
+
+func TestRows(t *testing.T) {
+	db := &DB{conf: &Config{logger: logger.Default.LogMode(logger.SilentLevel)}, clone: 1}
+	assert.NoError(t, db.Use(stubPlugin(func(ctx context.Context, query *Query) (*nebula.ResultSet, error) {
+		return newPlayersResult(t, "kobe", "james"), nil
+	})))
+
+	rows, err := db.Raw("LOOKUP ON player YIELD id(vertex) AS vid, properties(vertex).name AS name").Rows()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vid", "name"}, rows.Columns())
+	assert.Error(t, rows.Scan(new(rowsPlayer)))
+
+	players := make([]*rowsPlayer, 0)
+	for rows.Next() {
+		player := new(rowsPlayer)
+		assert.NoError(t, rows.Scan(player))
+		players = append(players, player)
+	}
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, []*rowsPlayer{{VID: "player_kobe", Name: "kobe", found: true}, {VID: "player_james", Name: "james", found: true}}, players)
+	assert.False(t, rows.Next())
+
+	rows, err = newRows(context.Background(), newPlayersResult(t, "kobe"))
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	var m map[string]any
+	assert.NoError(t, rows.Scan(&m))
+	assert.Equal(t, map[string]any{"vid": "player_kobe", "name": "kobe"}, m)
+	assert.ErrorIs(t, rows.Scan(rowsPlayer{}), ErrInvalidValue)
+	assert.NoError(t, rows.Close())
+	assert.False(t, rows.Next())
+
+	_, err = newRows(context.Background(), newFailedResult(t, nebulaType.ErrorCode_E_SEMANTIC_ERROR, "SemanticError"))
+	assert.True(t, IsSemanticError(err))
+}
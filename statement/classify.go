@@ -0,0 +1,204 @@
+package statement
+
+import (
+	"strings"
+	"unicode"
+)
+
+// readOnlyKeywords the leading keywords of the statements that only read data
+var readOnlyKeywords = map[string]bool{
+	"GO":       true,
+	"FETCH":    true,
+	"LOOKUP":   true,
+	"MATCH":    true,
+	"OPTIONAL": true,
+	"UNWIND":   true,
+	"WITH":     true,
+	"RETURN":   true,
+	"YIELD":    true,
+	"GROUP":    true,
+	"ORDER":    true,
+	"LIMIT":    true,
+	"FIND":     true,
+	"GET":      true,
+	"SHOW":     true,
+	"DESCRIBE": true,
+	"DESC":     true,
+	"EXPLAIN":  true,
+	"USE":      true,
+}
+
+// IsReadOnly reports whether the nGQL only reads data, every statement in a composite nGQL must be a read.
+// The nGQL is classified by the leading keywords of its statements, anything that is not recognized is considered
+// as a write.
+func IsReadOnly(nGQL string) bool {
+	stmts := splitStatements(nGQL)
+	if len(stmts) == 0 {
+		return false
+	}
+	for _, words := range stmts {
+		if !readOnlyKeywords[words[0]] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsIdempotent reports whether executing the nGQL more than once has the same effect as executing it once,
+// such as reads, INSERT ... IF NOT EXISTS, CREATE ... IF NOT EXISTS, DROP ... IF EXISTS, DELETE and CLEAR SPACE.
+// Other writes, eg: UPDATE which may set a prop based on its current value, are not considered idempotent.
+func IsIdempotent(nGQL string) bool {
+	stmts := splitStatements(nGQL)
+	if len(stmts) == 0 {
+		return false
+	}
+	for _, words := range stmts {
+		if readOnlyKeywords[words[0]] {
+			continue
+		}
+		switch words[0] {
+		case "DELETE", "CLEAR":
+		case "INSERT", "CREATE":
+			if !containsWords(words, "IF", "NOT", "EXISTS") {
+				return false
+			}
+		case "DROP":
+			if !containsWords(words, "IF", "EXISTS") {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// twoWordsKinds the statements whose kind consists of two leading keywords, eg: INSERT VERTEX, CREATE TAG
+var twoWordsKinds = map[string]bool{
+	"INSERT":   true,
+	"UPDATE":   true,
+	"UPSERT":   true,
+	"DELETE":   true,
+	"CREATE":   true,
+	"DROP":     true,
+	"CLEAR":    true,
+	"ALTER":    true,
+	"REBUILD":  true,
+	"SHOW":     true,
+	"DESCRIBE": true,
+	"DESC":     true,
+	"FIND":     true,
+	"GET":      true,
+	"SUBMIT":   true,
+	"STOP":     true,
+	"RECOVER":  true,
+}
+
+// Kind returns the kind of the nGQL, which is the leading keywords of its first statement, eg: GO, FETCH,
+// INSERT VERTEX, CREATE TAG. the statements switching the space are skipped.
+func Kind(nGQL string) string {
+	stmts := splitStatements(nGQL)
+	for i, words := range stmts {
+		if words[0] == "USE" && i < len(stmts)-1 {
+			continue
+		}
+		if twoWordsKinds[words[0]] && len(words) > 1 {
+			return words[0] + " " + words[1]
+		}
+		return words[0]
+	}
+	return ""
+}
+
+// leadingWordsLimit the number of leading words of a statement needed to classify it
+const leadingWordsLimit = 8
+
+// splitStatements splits the nGQL into statements by ';' and the pipe '|' outside of quotes, and returns the
+// leading words of each statement in upper case. the assignment of variables such as '$var = GO ...' is skipped.
+func splitStatements(nGQL string) [][]string {
+	stmts := make([][]string, 0, 1)
+	var (
+		words []string
+		word  strings.Builder
+		quote rune
+		skip  bool
+	)
+	flushWord := func() {
+		if word.Len() == 0 {
+			return
+		}
+		if len(words) < leadingWordsLimit {
+			words = append(words, strings.ToUpper(word.String()))
+		}
+		word.Reset()
+	}
+	flushStmt := func() {
+		flushWord()
+		// skip the variable assignment
+		if len(words) >= 2 && strings.HasPrefix(words[0], "$") && words[1] == "=" {
+			words = words[2:]
+		}
+		if len(words) > 0 {
+			stmts = append(stmts, words)
+		}
+		words = nil
+	}
+	runes := []rune(nGQL)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if quote != 0 {
+			switch {
+			case skip:
+				skip = false
+			case r == '\\':
+				skip = true
+			case r == quote:
+				quote = 0
+			}
+			continue
+		}
+		switch {
+		case r == '"' || r == '\'' || r == '`':
+			flushWord()
+			quote = r
+			// a quoted string is a single word, it is only needed to keep the position of the following words
+			if len(words) < leadingWordsLimit {
+				words = append(words, string(r))
+			}
+		case r == '|' && i+1 < len(runes) && runes[i+1] == '|':
+			// the logical operator '||'
+			flushWord()
+			i++
+		case r == ';' || r == '|':
+			flushStmt()
+		case r == '=':
+			flushWord()
+			if len(words) < leadingWordsLimit {
+				words = append(words, "=")
+			}
+		case unicode.IsSpace(r) || r == '(' || r == ')' || r == ',':
+			flushWord()
+		default:
+			word.WriteRune(r)
+		}
+	}
+	flushStmt()
+	return stmts
+}
+
+// containsWords reports whether the words contain the sub words in sequence
+func containsWords(words []string, sub ...string) bool {
+	for i := 0; i+len(sub) <= len(words); i++ {
+		matched := true
+		for j := range sub {
+			if words[i+j] != sub[j] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package statement
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		nGQL           string
+		readOnlyWant   bool
+		idempotentWant bool
+	}{
+		{
+			nGQL:           `GO FROM "player102" OVER serve YIELD dst(edge) AS id | FETCH PROP ON team $-.id YIELD vertex AS v;`,
+			readOnlyWant:   true,
+			idempotentWant: true,
+		},
+		{
+			nGQL:           `match (v:player) where v.player.age > 30 || v.player.name == "a;b|c" return v`,
+			readOnlyWant:   true,
+			idempotentWant: true,
+		},
+		{
+			nGQL:           `$var = GO FROM "player100" OVER follow YIELD dst(edge) AS id; GO FROM $var.id OVER serve;`,
+			readOnlyWant:   true,
+			idempotentWant: true,
+		},
+		{
+			nGQL:           `SHOW TAGS;`,
+			readOnlyWant:   true,
+			idempotentWant: true,
+		},
+		{
+			nGQL:           `INSERT VERTEX IF NOT EXISTS player(name, age) VALUES "player100":("Tim Duncan", 42);`,
+			idempotentWant: true,
+		},
+		{
+			nGQL: `INSERT VERTEX player(name, age) VALUES "player100":("IF NOT EXISTS", 42);`,
+		},
+		{
+			nGQL:           `CREATE TAG IF NOT EXISTS player(name string, age int); DROP TAG IF EXISTS team;`,
+			idempotentWant: true,
+		},
+		{
+			nGQL: `DROP TAG team;`,
+		},
+		{
+			nGQL:           `CLEAR SPACE basketballplayer;`,
+			idempotentWant: true,
+		},
+		{
+			nGQL:           `GO FROM "player100" OVER serve YIELD src(edge) AS src, dst(edge) AS dst | DELETE EDGE serve $-.src -> $-.dst;`,
+			idempotentWant: true,
+		},
+		{
+			nGQL: `UPDATE VERTEX ON player "player101" SET age = age + 2;`,
+		},
+		{
+			nGQL: `FETCH PROP ON player "player100" YIELD vertex AS v; UPDATE VERTEX ON player "player101" SET age = 1;`,
+		},
+		{
+			nGQL: ``,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("case #%d", i), func(t *testing.T) {
+			assert.Equal(t, tt.readOnlyWant, IsReadOnly(tt.nGQL))
+			assert.Equal(t, tt.idempotentWant, IsIdempotent(tt.nGQL))
+		})
+	}
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		nGQL string
+		want string
+	}{
+		{
+			nGQL: `GO FROM "player102" OVER serve YIELD dst(edge) AS id | FETCH PROP ON team $-.id YIELD vertex AS v;`,
+			want: "GO",
+		},
+		{
+			nGQL: "USE `tenant_42`; lookup on player yield id(vertex);",
+			want: "LOOKUP",
+		},
+		{
+			nGQL: `INSERT VERTEX IF NOT EXISTS player(name, age) VALUES "player100":("Tim Duncan", 42);`,
+			want: "INSERT VERTEX",
+		},
+		{
+			nGQL: `CREATE TAG IF NOT EXISTS player(name string, age int);`,
+			want: "CREATE TAG",
+		},
+		{
+			nGQL: `CLEAR SPACE IF EXISTS basketballplayer;`,
+			want: "CLEAR SPACE",
+		},
+		{
+			nGQL: "USE `tenant_42`; STOP JOB 7",
+			want: "STOP JOB",
+		},
+		{
+			nGQL: `$var = GO FROM "player100" OVER follow YIELD dst(edge) AS id; GO FROM $var.id OVER serve;`,
+			want: "GO",
+		},
+		{
+			nGQL: "USE basketballplayer",
+			want: "USE",
+		},
+		{
+			nGQL: "",
+			want: "",
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("case #%d", i), func(t *testing.T) {
+			assert.Equal(t, tt.want, Kind(tt.nGQL))
+		})
+	}
+}
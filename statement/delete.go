@@ -12,6 +12,12 @@ import "github.com/haysons/norm/clause"
 //
 // DELETE VERTEX $-.id
 // stmt.DeleteVertex(clause.Expr{Str: "$-.id"})
+//
+// vertexes that can be parsed (implementing the resolver.VertexIDStr or resolver.VertexIDInt64 interface) can also be
+// passed in directly, their vertex ids will be used
+//
+// DELETE VERTEX "player100", "player101"
+// stmt.DeleteVertex([]*player{{VID: "player100"}, {VID: "player101"}})
 func (stmt *Statement) DeleteVertex(vid any, withEdge ...bool) *Statement {
 	var withEdgeOpt bool
 	if len(withEdge) > 0 {
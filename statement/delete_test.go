@@ -25,6 +25,12 @@ func TestDelete(t *testing.T) {
 			},
 			want: `DELETE VERTEX "team1", "team2" WITH EDGE;`,
 		},
+		{
+			stmt: func() *Statement {
+				return New().DeleteVertex([]*t2{{VID: "team1"}, {VID: "team2"}})
+			},
+			want: `DELETE VERTEX "team1", "team2";`,
+		},
 		{
 			stmt: func() *Statement {
 				return New().Go().From("player100").Over("serve").Where("properties(edge).start_year == ?", "2021").Yield("dst(edge) AS id").Pipe().
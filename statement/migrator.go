@@ -473,3 +473,93 @@ func (stmt *Statement) DropEdgeIndex(indexName string, ifExists ...bool) *Statem
 	stmt.SetPartType(PartTypeDropIndex)
 	return stmt
 }
+
+// CreateSpace creates a graph space with the options.
+//
+// Examples:
+//
+//	stmt.CreateSpace("basketballplayer", clause.SpaceOptions{PartitionNum: 10, ReplicaFactor: 1, VIDType: resolver.VIDTypeString, FixedStringLength: 32}, true)
+//
+// Generates nGQL: CREATE SPACE IF NOT EXISTS basketballplayer(partition_num = 10, replica_factor = 1, vid_type = FIXED_STRING(32));
+func (stmt *Statement) CreateSpace(spaceName string, opts clause.SpaceOptions, ifNotExists ...bool) *Statement {
+	var notExistsOpt bool
+	if len(ifNotExists) > 0 {
+		notExistsOpt = ifNotExists[0]
+	}
+	stmt.AddClause(&clause.CreateSpace{
+		IfNotExists: notExistsOpt,
+		SpaceName:   spaceName,
+		Options:     opts,
+	})
+	stmt.SetPartType(PartTypeCreateSpace)
+	return stmt
+}
+
+// CreateSpaceAs creates a graph space with the same schema as the source space, the data is not copied.
+//
+// Examples:
+//
+//	stmt.CreateSpaceAs("basketballplayer_copy", "basketballplayer")
+//
+// Generates nGQL: CREATE SPACE basketballplayer_copy AS basketballplayer;
+func (stmt *Statement) CreateSpaceAs(spaceName, sourceSpace string, ifNotExists ...bool) *Statement {
+	var notExistsOpt bool
+	if len(ifNotExists) > 0 {
+		notExistsOpt = ifNotExists[0]
+	}
+	stmt.AddClause(&clause.CreateSpaceAs{
+		IfNotExists: notExistsOpt,
+		SpaceName:   spaceName,
+		SourceSpace: sourceSpace,
+	})
+	stmt.SetPartType(PartTypeCreateSpaceAs)
+	return stmt
+}
+
+// DropSpace drops a graph space along with all the schemas and data in it.
+// If the space name is empty, the operation is skipped.
+//
+// Examples:
+//
+//	stmt.DropSpace("basketballplayer", true)
+//
+// Generates nGQL: DROP SPACE IF EXISTS basketballplayer;
+func (stmt *Statement) DropSpace(spaceName string, ifExists ...bool) *Statement {
+	if spaceName == "" {
+		return stmt
+	}
+	var existsOpt bool
+	if len(ifExists) > 0 {
+		existsOpt = ifExists[0]
+	}
+	stmt.AddClause(&clause.DropSpace{
+		IfExists:  existsOpt,
+		SpaceName: spaceName,
+	})
+	stmt.SetPartType(PartTypeDropSpace)
+	return stmt
+}
+
+// ClearSpace deletes all the data in a graph space, the schemas are kept.
+// If the space name is empty, the operation is skipped.
+//
+// Examples:
+//
+//	stmt.ClearSpace("basketballplayer", true)
+//
+// Generates nGQL: CLEAR SPACE IF EXISTS basketballplayer;
+func (stmt *Statement) ClearSpace(spaceName string, ifExists ...bool) *Statement {
+	if spaceName == "" {
+		return stmt
+	}
+	var existsOpt bool
+	if len(ifExists) > 0 {
+		existsOpt = ifExists[0]
+	}
+	stmt.AddClause(&clause.ClearSpace{
+		IfExists:  existsOpt,
+		SpaceName: spaceName,
+	})
+	stmt.SetPartType(PartTypeClearSpace)
+	return stmt
+}
@@ -3,6 +3,7 @@ package statement
 import (
 	"fmt"
 	"github.com/haysons/norm/clause"
+	"github.com/haysons/norm/resolver"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -525,6 +526,148 @@ func TestDropEdgeIndex(t *testing.T) {
 	}
 }
 
+func TestCreateSpace(t *testing.T) {
+	tests := []struct {
+		stmt    func() *Statement
+		want    string
+		wantErr bool
+	}{
+		{
+			stmt: func() *Statement {
+				return New().CreateSpace("basketballplayer", clause.SpaceOptions{PartitionNum: 10, ReplicaFactor: 1, VIDType: resolver.VIDTypeString, FixedStringLength: 32}, true)
+			},
+			want: `CREATE SPACE IF NOT EXISTS basketballplayer(partition_num = 10, replica_factor = 1, vid_type = FIXED_STRING(32));`,
+		},
+		{
+			stmt: func() *Statement {
+				return New().CreateSpace("test", clause.SpaceOptions{VIDType: resolver.VIDTypeInt64, Comment: "test space"})
+			},
+			want: `CREATE SPACE test(vid_type = INT64) COMMENT = "test space";`,
+		},
+		{
+			stmt: func() *Statement {
+				return New().CreateSpace("test", clause.SpaceOptions{})
+			},
+			wantErr: true,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("#_%d", i), func(t *testing.T) {
+			s := tt.stmt()
+			ngql, err := s.NGQL()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, ngql)
+			}
+		})
+	}
+}
+
+func TestCreateSpaceAs(t *testing.T) {
+	tests := []struct {
+		stmt    func() *Statement
+		want    string
+		wantErr bool
+	}{
+		{
+			stmt: func() *Statement {
+				return New().CreateSpaceAs("basketballplayer_copy", "basketballplayer")
+			},
+			want: `CREATE SPACE basketballplayer_copy AS basketballplayer;`,
+		},
+		{
+			stmt: func() *Statement {
+				return New().CreateSpaceAs("basketballplayer_copy", "basketballplayer", true)
+			},
+			want: `CREATE SPACE IF NOT EXISTS basketballplayer_copy AS basketballplayer;`,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("#_%d", i), func(t *testing.T) {
+			s := tt.stmt()
+			ngql, err := s.NGQL()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, ngql)
+			}
+		})
+	}
+}
+
+func TestDropSpace(t *testing.T) {
+	tests := []struct {
+		stmt    func() *Statement
+		want    string
+		wantErr bool
+	}{
+		{
+			stmt: func() *Statement {
+				return New().DropSpace("basketballplayer")
+			},
+			want: `DROP SPACE basketballplayer;`,
+		},
+		{
+			stmt: func() *Statement {
+				return New().DropSpace("basketballplayer", true)
+			},
+			want: `DROP SPACE IF EXISTS basketballplayer;`,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("#_%d", i), func(t *testing.T) {
+			s := tt.stmt()
+			ngql, err := s.NGQL()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, ngql)
+			}
+		})
+	}
+}
+
+func TestClearSpace(t *testing.T) {
+	tests := []struct {
+		stmt    func() *Statement
+		want    string
+		wantErr bool
+	}{
+		{
+			stmt: func() *Statement {
+				return New().ClearSpace("basketballplayer")
+			},
+			want: `CLEAR SPACE basketballplayer;`,
+		},
+		{
+			stmt: func() *Statement {
+				return New().ClearSpace("basketballplayer", true)
+			},
+			want: `CLEAR SPACE IF EXISTS basketballplayer;`,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("#_%d", i), func(t *testing.T) {
+			s := tt.stmt()
+			ngql, err := s.NGQL()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, ngql)
+			}
+		})
+	}
+}
+
 type vm1 struct {
 	VID  string `norm:"vertex_id"`
 	Name string `norm:"index:,length:5"`
@@ -5,9 +5,17 @@ import (
 	"strings"
 )
 
-// Raw execute any statement
-func (stmt *Statement) Raw(raw string) *Statement {
-	stmt.nGQL.WriteString(raw)
+// Raw execute any statement, args replace the placeholders '?' in the statement in turn
+//
+// SHOW TAGS
+// stmt.Raw("SHOW TAGS")
+//
+// MATCH (v:player) WHERE v.player.age > 30 RETURN v
+// stmt.Raw("MATCH (v:player) WHERE v.player.age > ? RETURN v", 30)
+func (stmt *Statement) Raw(raw string, args ...any) *Statement {
+	if err := (clause.Expr{Str: raw, Vars: args}).Build(stmt.builder()); err != nil {
+		stmt.err = err
+	}
 	stmt.built = true
 	return stmt
 }
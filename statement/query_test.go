@@ -198,6 +198,78 @@ func TestQuery(t *testing.T) {
 			},
 			want: `GO FROM "player100" OVER follow YIELD dst(edge) AS id | GO FROM $-.id OVER serve YIELD properties($$).name AS Team, properties($^).name AS Player`,
 		},
+		{
+			stmt: func() *Statement {
+				return New().Raw(`MATCH (v:player) WHERE v.player.age > ? AND v.player.name IN ? RETURN v`, 30, []string{"Tim Duncan", "Yao Ming"})
+			},
+			want: `MATCH (v:player) WHERE v.player.age > 30 AND v.player.name IN ["Tim Duncan", "Yao Ming"] RETURN v`,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("#_%d", i), func(t *testing.T) {
+			s := tt.stmt()
+			ngql, err := s.NGQL()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, ngql)
+			}
+		})
+	}
+}
+
+func TestParameterize(t *testing.T) {
+	tests := []struct {
+		stmt       func() *Statement
+		want       string
+		wantParams map[string]any
+		wantErr    bool
+	}{
+		{
+			stmt: func() *Statement {
+				return New().Parameterize().
+					Lookup("player").
+					Where("player.name == ?", "Tim Duncan").
+					Or("player.age > ?", 30).
+					Yield("id(vertex)")
+			},
+			want:       `LOOKUP ON player WHERE player.name == $p1 OR player.age > $p2 YIELD id(vertex);`,
+			wantParams: map[string]any{"p1": "Tim Duncan", "p2": int64(30)},
+		},
+		{
+			stmt: func() *Statement {
+				return New().Parameterize().
+					Go().
+					From("player102").
+					Over("serve").
+					Where("properties(edge).start_year IN ?", []int{1997, 1998}).
+					Where("properties($$).name == ?", clause.Expr{Str: "properties($^).name"}).
+					Yield("dst(edge)")
+			},
+			want:       `GO FROM "player102" OVER serve WHERE properties(edge).start_year IN $p1 AND properties($$).name == properties($^).name YIELD dst(edge);`,
+			wantParams: map[string]any{"p1": []any{int64(1997), int64(1998)}},
+		},
+		{
+			stmt: func() *Statement {
+				return New().Parameterize().Raw(`MATCH (v:player) WHERE v.player.age > ? RETURN v`, 30)
+			},
+			want:       `MATCH (v:player) WHERE v.player.age > $p1 RETURN v`,
+			wantParams: map[string]any{"p1": int64(30)},
+		},
+		{
+			stmt: func() *Statement {
+				return New().Parameterize().Raw(`SHOW TAGS`)
+			},
+			want: `SHOW TAGS`,
+		},
+		{
+			stmt: func() *Statement {
+				return New().Parameterize().Lookup("player").Where("player.name == ?", struct{}{}).Yield("id(vertex)")
+			},
+			wantErr: true,
+		},
 	}
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("#_%d", i), func(t *testing.T) {
@@ -209,6 +281,10 @@ func TestQuery(t *testing.T) {
 			}
 			if assert.NoError(t, err) {
 				assert.Equal(t, tt.want, ngql)
+				assert.Equal(t, len(tt.wantParams), len(s.Params()))
+				for k, v := range tt.wantParams {
+					assert.Equal(t, v, s.Params()[k])
+				}
 			}
 		})
 	}
@@ -2,6 +2,9 @@ package statement
 
 import (
 	"github.com/haysons/norm/clause"
+	"github.com/haysons/norm/resolver"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -9,10 +12,12 @@ import (
 // A statement consists of multiple parts, which may be separated by '|', and each part consists of multiple clauses
 // that independently construct their own part of the statement. The statement object is not concurrency safe.
 type Statement struct {
-	parts []*Part
-	nGQL  *strings.Builder
-	built bool
-	err   error
+	parts         []*Part
+	nGQL          *strings.Builder
+	built         bool
+	err           error
+	parameterized bool
+	params        map[string]any
 }
 
 func New() *Statement {
@@ -86,7 +91,7 @@ func (stmt *Statement) Build() error {
 			}
 		}
 		firstPartBuilt = true
-		if err := part.Build(stmt.nGQL); err != nil {
+		if err := part.Build(stmt.builder()); err != nil {
 			stmt.err = err
 			break
 		}
@@ -104,6 +109,96 @@ func (stmt *Statement) NGQL() (string, error) {
 	return stmt.nGQL.String(), nil
 }
 
+// Parameterize makes the variables of expressions (eg: the arguments of Where, When and Raw) be built as query
+// parameters such as $p1, the values are sent to the server along with the statement instead of being inlined into
+// it, this keeps the statement text stable and leaves no room for injection.
+// Note: parameters are resolved by the server, refer to the nebula graph documentation for the statements that
+// accept them.
+//
+// WHERE v.player.name == $p1, params: {"p1": "Tim Duncan"}
+// stmt.Parameterize().Where("v.player.name == ?", "Tim Duncan")
+func (stmt *Statement) Parameterize() *Statement {
+	stmt.parameterized = true
+	return stmt
+}
+
+// Params returns the query parameters collected while building the statement, it is empty unless the statement
+// is parameterized
+func (stmt *Statement) Params() map[string]any {
+	return stmt.params
+}
+
+// Page builds the statement and returns a new statement which pipes its result into a limit clause, it is used to
+// page through the result of GO and LOOKUP statements. the query parameters of the statement are kept.
+// Note: the order of the result is not guaranteed without ORDER BY, the pages may overlap or miss some records.
+//
+// LOOKUP ON player YIELD id(vertex) AS id | ORDER BY $-.id | LIMIT 200, 100
+// stmt.Lookup("player").Yield("id(vertex) AS id").OrderBy("$-.id").Page(200, 100)
+func (stmt *Statement) Page(offset, limit int) (*Statement, error) {
+	return stmt.pipeInto(clause.Limit{Limit: limit, Offset: offset})
+}
+
+// Aggregate builds the statement and returns a new statement which pipes its result into a yield clause, it is
+// used to aggregate the result of the statement. the query parameters of the statement are kept.
+//
+// GO FROM "player100" OVER follow YIELD dst(edge) AS id | YIELD count(*) AS count
+// stmt.Go().From("player100").Over("follow").Yield("dst(edge) AS id").Aggregate("count(*) AS count")
+func (stmt *Statement) Aggregate(expr string) (*Statement, error) {
+	return stmt.pipeInto(clause.Yield{ExprList: []string{expr}})
+}
+
+// pipeInto builds the statement and returns a new statement which pipes its result into the clause. raw statements
+// are supported as well, since the clause is appended to the nGQL built.
+func (stmt *Statement) pipeInto(c clause.Expression) (*Statement, error) {
+	nGQL, err := stmt.NGQL()
+	if err != nil {
+		return nil, err
+	}
+	piped := New()
+	piped.parameterized = stmt.parameterized
+	if len(stmt.params) > 0 {
+		piped.params = make(map[string]any, len(stmt.params))
+		for name, value := range stmt.params {
+			piped.params[name] = value
+		}
+	}
+	piped.nGQL.WriteString(strings.TrimSuffix(strings.TrimSpace(nGQL), ";"))
+	piped.nGQL.WriteString(" | ")
+	if err = c.Build(piped.nGQL); err != nil {
+		return nil, err
+	}
+	piped.nGQL.WriteByte(';')
+	piped.built = true
+	return piped, nil
+}
+
+// builder returns the builder used to build the clauses of the statement
+func (stmt *Statement) builder() clause.Builder {
+	if !stmt.parameterized {
+		return stmt.nGQL
+	}
+	if stmt.params == nil {
+		stmt.params = make(map[string]any)
+	}
+	return &paramBuilder{Builder: stmt.nGQL, params: stmt.params}
+}
+
+// paramBuilder collects the variables of expressions into the params of the statement
+type paramBuilder struct {
+	*strings.Builder
+	params map[string]any
+}
+
+func (b *paramBuilder) AddParam(value any) (string, error) {
+	paramValue, err := resolver.FormatParamValue(reflect.ValueOf(value))
+	if err != nil {
+		return "", err
+	}
+	name := "p" + strconv.Itoa(len(b.params)+1)
+	b.params[name] = paramValue
+	return "$" + name, nil
+}
+
 // Part is the part of the statement that actually contains the clause to be constructed and completes the construction
 // of the statement by calling the clause's Build method. Because the concept of a compound statement exists in nGQL,
 // it is necessary to add another layer to the statement concept to generate each part of the compound statement
@@ -210,6 +305,10 @@ const (
 	PartTypeCreateIndex
 	PartTypeRebuildIndex
 	PartTypeDropIndex
+	PartTypeCreateSpace
+	PartTypeCreateSpaceAs
+	PartTypeDropSpace
+	PartTypeClearSpace
 )
 
 func (p *Part) getClausesBuild() []string {
@@ -259,6 +358,14 @@ func (p *Part) getClausesBuild() []string {
 		return []string{clause.RebuildIndexName}
 	case PartTypeDropIndex:
 		return []string{clause.DropIndexName}
+	case PartTypeCreateSpace:
+		return []string{clause.CreateSpaceName}
+	case PartTypeCreateSpaceAs:
+		return []string{clause.CreateSpaceAsName}
+	case PartTypeDropSpace:
+		return []string{clause.DropSpaceName}
+	case PartTypeClearSpace:
+		return []string{clause.ClearSpaceName}
 	default:
 		// The following clauses may not belong to a specific type of statement and can be used separately
 		return []string{clause.GroupName, clause.YieldName, clause.OrderName, clause.LimitName}
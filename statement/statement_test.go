@@ -57,3 +57,111 @@ func TestStatement(t *testing.T) {
 		})
 	}
 }
+
+func TestPage(t *testing.T) {
+	tests := []struct {
+		stmt       func() *Statement
+		offset     int
+		limit      int
+		want       string
+		wantParams map[string]any
+		wantErr    bool
+	}{
+		{
+			stmt: func() *Statement {
+				return New().Lookup("player").Yield("id(vertex) AS id").OrderBy("$-.id")
+			},
+			offset: 0,
+			limit:  100,
+			want:   `LOOKUP ON player YIELD id(vertex) AS id | ORDER BY $-.id | LIMIT 100;`,
+		},
+		{
+			stmt: func() *Statement {
+				return New().Go().From("player102").Over("serve").Yield("dst(edge) AS id")
+			},
+			offset: 200,
+			limit:  100,
+			want:   `GO FROM "player102" OVER serve YIELD dst(edge) AS id | LIMIT 200, 100;`,
+		},
+		{
+			stmt: func() *Statement {
+				return New().Parameterize().Raw(`LOOKUP ON player WHERE player.age > ? YIELD id(vertex) AS id`, 30)
+			},
+			offset:     10,
+			limit:      10,
+			want:       `LOOKUP ON player WHERE player.age > $p1 YIELD id(vertex) AS id | LIMIT 10, 10;`,
+			wantParams: map[string]any{"p1": int64(30)},
+		},
+		{
+			stmt: func() *Statement {
+				return New().Lookup("player").Yield("id(vertex) AS id")
+			},
+			limit:   -1,
+			wantErr: true,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("#_%d", i), func(t *testing.T) {
+			stmt := tt.stmt()
+			page, err := stmt.Page(tt.offset, tt.limit)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				ngql, err := page.NGQL()
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, ngql)
+				assert.Equal(t, tt.wantParams, page.Params())
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		stmt       func() *Statement
+		expr       string
+		want       string
+		wantParams map[string]any
+		wantErr    bool
+	}{
+		{
+			stmt: func() *Statement {
+				return New().Go().From("player100").Over("follow").Yield("dst(edge) AS id")
+			},
+			expr: "count(*) AS count",
+			want: `GO FROM "player100" OVER follow YIELD dst(edge) AS id | YIELD count(*) AS count;`,
+		},
+		{
+			stmt: func() *Statement {
+				return New().Parameterize().Raw(`LOOKUP ON player WHERE player.age > ? YIELD properties(vertex).age AS age`, 30)
+			},
+			expr:       "avg($-.age) AS age",
+			want:       `LOOKUP ON player WHERE player.age > $p1 YIELD properties(vertex).age AS age | YIELD avg($-.age) AS age;`,
+			wantParams: map[string]any{"p1": int64(30)},
+		},
+		{
+			stmt: func() *Statement {
+				return New().Lookup("player").Yield("id(vertex) AS id")
+			},
+			expr:    "",
+			wantErr: true,
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("#_%d", i), func(t *testing.T) {
+			stmt, err := tt.stmt().Aggregate(tt.expr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if assert.NoError(t, err) {
+				ngql, err := stmt.NGQL()
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, ngql)
+				assert.Equal(t, tt.wantParams, stmt.Params())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package norm
+
+import (
+	"sync/atomic"
+)
+
+// Stats the statistics of the session pools, the replicas are included. the sessions are counted only if the
+// executor reports them by GetTotalSessionCount, such as nebula.SessionPool
+type Stats struct {
+	// TotalSessions number of sessions created by the session pools, including the ones in use and the idle ones
+	TotalSessions int
+
+	// InUse number of statements being executed, each of them holds a session
+	InUse int
+
+	// Idle number of sessions that are not in use
+	Idle int
+}
+
+// Stats returns the statistics of the session pools. Note: nebula.SessionPool does not queue the executions
+// waiting for a session, it creates a new session instead, or fails if the pool is full, so there is no count of
+// waiting executions.
+func (db *DB) Stats() Stats {
+	var stats Stats
+	stats.TotalSessions = totalSessions(db.executor)
+	if db.replicas != nil {
+		for _, executor := range db.replicas.executors {
+			stats.TotalSessions += totalSessions(executor)
+		}
+	}
+	if db.inUse != nil {
+		stats.InUse = int(atomic.LoadInt64(db.inUse))
+	}
+	if stats.Idle = stats.TotalSessions - stats.InUse; stats.Idle < 0 {
+		stats.Idle = 0
+	}
+	return stats
+}
+
+// totalSessions returns the number of sessions created by the executor, 0 if it does not report it
+func totalSessions(executor Executor) int {
+	if counter, ok := executor.(interface{ GetTotalSessionCount() int }); ok {
+		return counter.GetTotalSessionCount()
+	}
+	return 0
+}
+
+// trackInUse counts the statement being executed, the returned function should be called when it is done
+func (db *DB) trackInUse() func() {
+	if db.inUse == nil {
+		return func() {}
+	}
+	atomic.AddInt64(db.inUse, 1)
+	return func() {
+		atomic.AddInt64(db.inUse, -1)
+	}
+}
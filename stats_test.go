@@ -0,0 +1,21 @@
+package norm
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	db := &DB{conf: &Config{}, inUse: new(int64), clone: 1}
+	assert.Equal(t, Stats{}, db.Stats())
+
+	done1 := db.trackInUse()
+	done2 := db.Fetch("player", "player100").trackInUse()
+	assert.Equal(t, Stats{InUse: 2}, db.Stats())
+	done1()
+	done2()
+	assert.Equal(t, Stats{}, db.Stats())
+
+	// trackInUse is a no-op for the DB not created by Open
+	(&DB{}).trackInUse()()
+}